@@ -0,0 +1,439 @@
+package ddl
+
+import (
+	"slices"
+
+	"github.com/miyataka/sqlgen"
+)
+
+func (a *applier) alterTable(p *parser) error {
+	ifExists := p.accept("IF", "EXISTS")
+	p.accept("ONLY")
+	schema, name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	p.accept("*")
+	t := a.table(schema, name)
+	if t == nil {
+		if ifExists {
+			return nil
+		}
+		return p.errorf("table %s does not exist", name)
+	}
+	for !p.done() {
+		if err := a.alterAction(p, t); err != nil {
+			return err
+		}
+		// skip the rest of actions that do not change the model
+		p.collect(nil)
+		if !p.accept(",") {
+			break
+		}
+	}
+	return nil
+}
+
+// alterAction applies a single action of an ALTER TABLE statement.
+func (a *applier) alterAction(p *parser, t *sqlgen.Table) error {
+	switch {
+	case p.accept("ADD"):
+		if a.atConstraint(p) {
+			return a.constraint(p, t)
+		}
+		p.accept("COLUMN")
+		if p.accept("IF", "NOT", "EXISTS") {
+			start := p.pos
+			if name, err := p.ident(); err == nil && t.Column(name) != nil {
+				return nil
+			}
+			p.pos = start
+		}
+		_, err := a.column(p, t, len(t.Columns))
+		return err
+	case p.accept("DROP"):
+		return a.alterDrop(p, t)
+	case p.accept("RENAME"):
+		return a.alterRename(p, t)
+	case p.accept("ALTER"):
+		p.accept("COLUMN")
+		c, err := a.existingColumn(p, t)
+		if err != nil {
+			return err
+		}
+		switch {
+		case p.accept("SET", "DEFAULT"):
+			a.setDefault(c, p.expression())
+		case p.accept("DROP", "DEFAULT"):
+			c.Default = nil
+		case p.accept("SET", "NOT", "NULL"):
+			c.Nullable = false
+		case p.accept("DROP", "NOT", "NULL"):
+			c.Nullable = true
+		case p.accept("ADD", "GENERATED"):
+			return a.generated(p, c)
+		case p.accept("SET", "GENERATED"):
+			if c.Identity != "" {
+				c.Identity = sqlgen.IdentityAlways
+				if p.accept("BY", "DEFAULT") {
+					c.Identity = sqlgen.IdentityByDefault
+				}
+			}
+		case p.accept("DROP", "IDENTITY"):
+			c.AutoIncrement = false
+			c.Identity = ""
+		case p.accept("DROP", "EXPRESSION"):
+			c.Generated = false
+		case p.accept("SET", "DATA", "TYPE"), p.accept("TYPE"):
+			typeTokens := p.collect(func(t token) bool { return t.is("USING") || t.is("COLLATE") })
+			c.DataType, _, c.Unsigned = a.columnType(typeTokens)
+		}
+	case !a.postgres && p.accept("MODIFY"):
+		p.accept("COLUMN")
+		start := p.pos
+		c, err := a.existingColumn(p, t)
+		if err != nil {
+			return err
+		}
+		p.pos = start
+		return a.redefineColumn(p, t, c)
+	case !a.postgres && p.accept("CHANGE"):
+		p.accept("COLUMN")
+		c, err := a.existingColumn(p, t)
+		if err != nil {
+			return err
+		}
+		return a.redefineColumn(p, t, c)
+	}
+	return nil
+}
+
+// existingColumn consumes the name of a column of t.
+func (a *applier) existingColumn(p *parser, t *sqlgen.Table) (*sqlgen.Column, error) {
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	c := t.Column(name)
+	if c == nil {
+		return nil, p.errorf("column %s of table %s does not exist", name, t.Name)
+	}
+	return c, nil
+}
+
+// redefineColumn replaces old by the column defined by the following tokens,
+// keeping its position unless FIRST or AFTER moves it.
+func (a *applier) redefineColumn(p *parser, t *sqlgen.Table, old *sqlgen.Column) error {
+	i := slices.Index(t.Columns, old)
+	t.Columns = slices.Delete(t.Columns, i, i+1)
+	c, err := a.column(p, t, i)
+	if err != nil {
+		return err
+	}
+	a.renameColumn(t, old.Name, c.Name)
+	if t.IsPrimaryKey(c.Name) {
+		c.Nullable = false
+	}
+	return nil
+}
+
+// alterDrop applies a DROP action of an ALTER TABLE statement.
+func (a *applier) alterDrop(p *parser, t *sqlgen.Table) error {
+	switch {
+	case p.accept("CONSTRAINT"), !a.postgres && p.accept("FOREIGN", "KEY"),
+		!a.postgres && p.accept("INDEX"), !a.postgres && p.accept("KEY"),
+		!a.postgres && p.accept("CHECK"):
+		p.accept("IF", "EXISTS")
+		name, err := p.ident()
+		if err != nil {
+			return err
+		}
+		a.dropConstraint(t, name)
+	case !a.postgres && p.accept("PRIMARY", "KEY"):
+		t.PrimaryKey = nil
+	default:
+		p.accept("COLUMN")
+		ifExists := p.accept("IF", "EXISTS")
+		name, err := p.ident()
+		if err != nil {
+			return err
+		}
+		if t.Column(name) == nil {
+			if ifExists {
+				return nil
+			}
+			return p.errorf("column %s of table %s does not exist", name, t.Name)
+		}
+		a.dropColumn(t, name)
+	}
+	return nil
+}
+
+// alterRename applies a RENAME action of an ALTER TABLE statement.
+func (a *applier) alterRename(p *parser, t *sqlgen.Table) error {
+	var kind string
+	switch {
+	case p.accept("COLUMN"):
+		kind = "column"
+	case p.accept("CONSTRAINT"), !a.postgres && p.accept("INDEX"), !a.postgres && p.accept("KEY"):
+		kind = "constraint"
+	case p.accept("TO"), p.accept("AS"), !p.peekAt(1).is("TO"):
+		_, name, err := p.qualifiedName()
+		if err != nil {
+			return err
+		}
+		a.renameTable(t, name)
+		return nil
+	default:
+		// PostgreSQL accepts RENAME old TO new for columns
+		kind = "column"
+	}
+
+	from, err := p.ident()
+	if err != nil {
+		return err
+	}
+	if err := p.expect("TO"); err != nil {
+		return err
+	}
+	to, err := p.ident()
+	if err != nil {
+		return err
+	}
+	if kind == "constraint" {
+		a.renameConstraint(t, from, to)
+		return nil
+	}
+	c := t.Column(from)
+	if c == nil {
+		return p.errorf("column %s of table %s does not exist", from, t.Name)
+	}
+	c.Name = to
+	a.renameColumn(t, from, to)
+	return nil
+}
+
+// dropTable applies a DROP TABLE statement.
+func (a *applier) dropTable(p *parser) error {
+	ifExists := p.accept("IF", "EXISTS")
+	for {
+		schema, name, err := p.qualifiedName()
+		if err != nil {
+			return err
+		}
+		t := a.table(schema, name)
+		switch {
+		case t != nil:
+			a.schema.Tables = slices.DeleteFunc(a.schema.Tables, func(other *sqlgen.Table) bool { return other == t })
+			a.dropReferences(t, nil)
+		case !ifExists:
+			return p.errorf("table %s does not exist", name)
+		}
+		if !p.accept(",") {
+			return nil
+		}
+	}
+}
+
+// dropIndex applies a DROP INDEX statement. Indexes that are not part of the
+// model, e.g. partial or expression indexes, are dropped silently.
+func (a *applier) dropIndex(p *parser) error {
+	p.accept("CONCURRENTLY")
+	p.accept("IF", "EXISTS")
+	for {
+		schema, name, err := p.qualifiedName()
+		if err != nil {
+			return err
+		}
+		if p.accept("ON") {
+			// MySQL names the table of the index
+			tableSchema, table, err := p.qualifiedName()
+			if err != nil {
+				return err
+			}
+			if t := a.table(tableSchema, table); t != nil {
+				a.dropConstraint(t, name)
+			}
+		} else if t := a.indexTable(schema, name); t != nil {
+			a.dropConstraint(t, name)
+		}
+		if !p.accept(",") {
+			return nil
+		}
+	}
+}
+
+// alterIndex applies an ALTER INDEX ... RENAME TO statement.
+func (a *applier) alterIndex(p *parser) error {
+	p.accept("IF", "EXISTS")
+	schema, name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	if !p.accept("RENAME", "TO") {
+		return nil
+	}
+	to, err := p.ident()
+	if err != nil {
+		return err
+	}
+	if t := a.indexTable(schema, name); t != nil {
+		a.renameConstraint(t, name, to)
+	}
+	return nil
+}
+
+// renameTables applies a MySQL RENAME TABLE statement.
+func (a *applier) renameTables(p *parser) error {
+	for {
+		schema, name, err := p.qualifiedName()
+		if err != nil {
+			return err
+		}
+		t := a.table(schema, name)
+		if t == nil {
+			return p.errorf("table %s does not exist", name)
+		}
+		if err := p.expect("TO"); err != nil {
+			return err
+		}
+		_, to, err := p.qualifiedName()
+		if err != nil {
+			return err
+		}
+		a.renameTable(t, to)
+		if !p.accept(",") {
+			return nil
+		}
+	}
+}
+
+// indexTable returns the table of schema owning the named index or primary
+// key constraint, or nil if there is none.
+func (a *applier) indexTable(schema, name string) *sqlgen.Table {
+	schema = a.schemaName(schema)
+	for _, t := range a.schema.Tables {
+		if t.Schema != schema {
+			continue
+		}
+		if t.Index(name) != nil || t.PrimaryKey != nil && t.PrimaryKey.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// dropConstraint removes the named primary key, unique constraint, index or
+// foreign key of t. Constraints that are not part of the model, e.g. CHECK
+// constraints, are ignored.
+func (a *applier) dropConstraint(t *sqlgen.Table, name string) {
+	if t.PrimaryKey != nil && t.PrimaryKey.Name == name {
+		t.PrimaryKey = nil
+	}
+	t.Indexes = slices.DeleteFunc(t.Indexes, func(index *sqlgen.Index) bool { return index.Name == name })
+	t.ForeignKeys = slices.DeleteFunc(t.ForeignKeys, func(fk *sqlgen.ForeignKey) bool { return fk.Name == name })
+}
+
+// renameConstraint renames the named primary key, index or foreign key of t.
+func (a *applier) renameConstraint(t *sqlgen.Table, from, to string) {
+	if t.PrimaryKey != nil && t.PrimaryKey.Name == from {
+		t.PrimaryKey.Name = to
+	}
+	if index := t.Index(from); index != nil {
+		index.Name = to
+	}
+	if fk := t.ForeignKey(from); fk != nil {
+		fk.Name = to
+	}
+}
+
+// dropColumn removes the named column of t together with the constraints
+// and indexes using it, as PostgreSQL and MySQL do.
+func (a *applier) dropColumn(t *sqlgen.Table, name string) {
+	t.Columns = slices.DeleteFunc(t.Columns, func(c *sqlgen.Column) bool { return c.Name == name })
+	renumber(t)
+	if t.IsPrimaryKey(name) {
+		t.PrimaryKey = nil
+	}
+	t.Indexes = slices.DeleteFunc(t.Indexes, func(index *sqlgen.Index) bool { return slices.Contains(index.Columns, name) })
+	t.ForeignKeys = slices.DeleteFunc(t.ForeignKeys, func(fk *sqlgen.ForeignKey) bool { return slices.Contains(fk.Columns, name) })
+	a.dropReferences(t, &name)
+}
+
+// dropReferences removes the foreign keys referencing t, or only its column
+// when column is not nil.
+func (a *applier) dropReferences(t *sqlgen.Table, column *string) {
+	for _, other := range a.schema.Tables {
+		other.ForeignKeys = slices.DeleteFunc(other.ForeignKeys, func(fk *sqlgen.ForeignKey) bool {
+			return references(fk, t) && (column == nil || slices.Contains(fk.ReferencedColumns, *column))
+		})
+	}
+}
+
+// renameColumn replaces the column name from by to in the constraints and
+// indexes of t and in the foreign keys referencing it.
+func (a *applier) renameColumn(t *sqlgen.Table, from, to string) {
+	if from == to {
+		return
+	}
+	if t.PrimaryKey != nil {
+		replace(t.PrimaryKey.Columns, from, to)
+	}
+	for _, index := range t.Indexes {
+		replace(index.Columns, from, to)
+	}
+	for _, fk := range t.ForeignKeys {
+		replace(fk.Columns, from, to)
+	}
+	for _, other := range a.schema.Tables {
+		for _, fk := range other.ForeignKeys {
+			if references(fk, t) {
+				replace(fk.ReferencedColumns, from, to)
+			}
+		}
+	}
+}
+
+// renameTable renames t and updates the foreign keys referencing it.
+func (a *applier) renameTable(t *sqlgen.Table, name string) {
+	for _, other := range a.schema.Tables {
+		for _, fk := range other.ForeignKeys {
+			if references(fk, t) {
+				fk.ReferencedTable = name
+			}
+		}
+	}
+	t.Name = name
+}
+
+// references reports whether fk references t.
+func references(fk *sqlgen.ForeignKey, t *sqlgen.Table) bool {
+	return fk.ReferencedSchema == t.Schema && fk.ReferencedTable == t.Name
+}
+
+// replace replaces from by to in names.
+func replace(names []string, from, to string) {
+	for i, name := range names {
+		if name == from {
+			names[i] = to
+		}
+	}
+}
+
+// moveColumn moves c to index i of the columns of t, counted before the move.
+func moveColumn(t *sqlgen.Table, c *sqlgen.Column, i int) {
+	from := slices.Index(t.Columns, c)
+	t.Columns = slices.Delete(t.Columns, from, from+1)
+	if i > from {
+		i--
+	}
+	t.Columns = slices.Insert(t.Columns, i, c)
+	renumber(t)
+}
+
+// renumber assigns the ordinal positions of the columns of t after a change.
+func renumber(t *sqlgen.Table) {
+	for i, c := range t.Columns {
+		c.OrdinalPosition = i + 1
+	}
+}
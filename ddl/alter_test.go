@@ -0,0 +1,130 @@
+package ddl
+
+import (
+	"testing"
+
+	"github.com/miyataka/sqlgen"
+)
+
+func TestApplyChangesPostgres(t *testing.T) {
+	schema, err := Parse(sqlgen.PostgresDialect{}, `
+	CREATE TABLE users (id serial PRIMARY KEY, email text UNIQUE, nick text, legacy int);
+	CREATE TABLE posts (id serial PRIMARY KEY, author_id int REFERENCES users (id), body text);
+	CREATE TABLE drafts (id int);
+	CREATE INDEX posts_body_idx ON posts (body);
+
+	ALTER TABLE users DROP COLUMN legacy, DROP COLUMN IF EXISTS missing;
+	ALTER TABLE users RENAME COLUMN id TO user_id;
+	ALTER TABLE users RENAME nick TO nickname;
+	ALTER TABLE users RENAME CONSTRAINT users_email_key TO users_email_uq;
+	ALTER TABLE users RENAME TO accounts;
+	ALTER TABLE accounts ALTER COLUMN nickname SET DATA TYPE varchar(50) USING nickname::varchar;
+	ALTER INDEX posts_body_idx RENAME TO posts_text_idx;
+	ALTER TABLE posts DROP CONSTRAINT posts_pkey;
+	DROP INDEX IF EXISTS posts_text_idx, never_created_idx;
+	DROP TABLE IF EXISTS drafts, never_created CASCADE;
+	`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	want := &sqlgen.Schema{
+		Name: "public",
+		Tables: []*sqlgen.Table{
+			{
+				Schema: "public",
+				Name:   "accounts",
+				Columns: []*sqlgen.Column{
+					{Name: "user_id", OrdinalPosition: 1, DataType: "integer", Default: ptr("nextval('users_id_seq'::regclass)"), AutoIncrement: true},
+					{Name: "email", OrdinalPosition: 2, DataType: "text", Nullable: true},
+					{Name: "nickname", OrdinalPosition: 3, DataType: "character varying", Nullable: true},
+				},
+				PrimaryKey: &sqlgen.PrimaryKey{Name: "users_pkey", Columns: []string{"user_id"}},
+				Indexes: []*sqlgen.Index{
+					{Name: "users_email_uq", Columns: []string{"email"}, Unique: true},
+				},
+			},
+			{
+				Schema: "public",
+				Name:   "posts",
+				Columns: []*sqlgen.Column{
+					{Name: "id", OrdinalPosition: 1, DataType: "integer", Default: ptr("nextval('posts_id_seq'::regclass)"), AutoIncrement: true},
+					{Name: "author_id", OrdinalPosition: 2, DataType: "integer", Nullable: true},
+					{Name: "body", OrdinalPosition: 3, DataType: "text", Nullable: true},
+				},
+				ForeignKeys: []*sqlgen.ForeignKey{
+					{
+						Name:              "posts_author_id_fkey",
+						Columns:           []string{"author_id"},
+						ReferencedSchema:  "public",
+						ReferencedTable:   "accounts",
+						ReferencedColumns: []string{"user_id"},
+					},
+				},
+			},
+		},
+	}
+	assertSchema(t, schema, want)
+}
+
+func TestApplyChangesMySQL(t *testing.T) {
+	schema, err := Parse(sqlgen.MySQLDialect{}, "CREATE TABLE users (\n"+
+		"  id int NOT NULL,\n"+
+		"  email varchar(255),\n"+
+		"  name text,\n"+
+		"  PRIMARY KEY (id),\n"+
+		"  UNIQUE KEY users_email (email)\n"+
+		");\n"+
+		"CREATE TABLE posts (id int PRIMARY KEY, user_id int, CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id));\n"+
+		"ALTER TABLE users MODIFY id bigint NOT NULL AUTO_INCREMENT;\n"+
+		"ALTER TABLE users CHANGE COLUMN email mail varchar(100) NOT NULL AFTER name;\n"+
+		"ALTER TABLE users ADD COLUMN created_at datetime FIRST;\n"+
+		"ALTER TABLE users RENAME INDEX users_email TO users_mail;\n"+
+		"ALTER TABLE posts DROP FOREIGN KEY fk_user, DROP PRIMARY KEY;\n"+
+		"RENAME TABLE users TO members;\n"+
+		"DROP INDEX users_mail ON members;\n")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	want := &sqlgen.Schema{
+		Tables: []*sqlgen.Table{
+			{
+				Name: "members",
+				Columns: []*sqlgen.Column{
+					{Name: "created_at", OrdinalPosition: 1, DataType: "datetime", Nullable: true},
+					{Name: "id", OrdinalPosition: 2, DataType: "bigint", AutoIncrement: true},
+					{Name: "name", OrdinalPosition: 3, DataType: "text", Nullable: true},
+					{Name: "mail", OrdinalPosition: 4, DataType: "varchar"},
+				},
+				PrimaryKey: &sqlgen.PrimaryKey{Name: "PRIMARY", Columns: []string{"id"}},
+			},
+			{
+				Name: "posts",
+				Columns: []*sqlgen.Column{
+					{Name: "id", OrdinalPosition: 1, DataType: "int"},
+					{Name: "user_id", OrdinalPosition: 2, DataType: "int", Nullable: true},
+				},
+			},
+		},
+	}
+	assertSchema(t, schema, want)
+}
+
+func TestApplyDropColumnDropsReferences(t *testing.T) {
+	schema, err := Parse(sqlgen.PostgresDialect{}, `
+	CREATE TABLE orders (id int, region int, PRIMARY KEY (id, region), UNIQUE (region, id));
+	CREATE TABLE items (order_id int, region int, FOREIGN KEY (order_id, region) REFERENCES orders);
+	ALTER TABLE orders DROP COLUMN region CASCADE;
+	`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	orders, items := schema.Table("orders"), schema.Table("items")
+	if orders.PrimaryKey != nil || len(orders.Indexes) != 0 {
+		t.Errorf("expected the key and index of orders to be dropped: %s", describe(orders))
+	}
+	if len(items.ForeignKeys) != 0 {
+		t.Errorf("expected the foreign key of items to be dropped: %s", describe(items))
+	}
+}
@@ -0,0 +1,777 @@
+package ddl
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/miyataka/sqlgen"
+)
+
+// applier applies DDL statements to a schema.
+type applier struct {
+	schema   *sqlgen.Schema
+	postgres bool
+	// types maps the names of the types created by CREATE TYPE and CREATE
+	// DOMAIN to the data type introspection reports for their columns.
+	types map[string]string
+}
+
+func newApplier(schema *sqlgen.Schema, d sqlgen.Dialect) *applier {
+	return &applier{schema: schema, postgres: d.Name() == "postgres", types: map[string]string{}}
+}
+
+func (a *applier) apply(src string) error {
+	tokens, err := tokenize(src, !a.postgres)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range splitStatements(tokens) {
+		p := &parser{tokens: stmt, foldCase: a.postgres}
+		if err := a.statement(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// statement applies a single statement, ignoring the kinds that do not change
+// tables or indexes.
+func (a *applier) statement(p *parser) error {
+	switch {
+	case p.accept("CREATE"):
+		p.accept("OR", "REPLACE")
+		if p.accept("TEMPORARY") || p.accept("TEMP") || p.accept("GLOBAL") || p.accept("LOCAL") {
+			// temporary tables are not part of the schema
+			return nil
+		}
+		p.accept("UNLOGGED")
+		switch {
+		case p.accept("TABLE"):
+			return a.createTable(p, sqlgen.KindBaseTable)
+		case p.accept("FOREIGN", "TABLE"):
+			return a.createTable(p, sqlgen.KindForeignTable)
+		case p.peek().is("INDEX"), p.peek().is("UNIQUE"):
+			return a.createIndex(p)
+		case a.postgres && p.accept("TYPE"):
+			return a.createType(p)
+		case a.postgres && p.accept("DOMAIN"):
+			return a.createDomain(p)
+		}
+	case p.accept("ALTER", "TABLE"):
+		return a.alterTable(p)
+	case p.accept("ALTER", "INDEX"):
+		return a.alterIndex(p)
+	case p.accept("DROP", "TABLE"):
+		return a.dropTable(p)
+	case p.accept("DROP", "INDEX"):
+		return a.dropIndex(p)
+	case p.accept("RENAME", "TABLE"):
+		return a.renameTables(p)
+	case a.postgres && (p.accept("ALTER", "TYPE") || p.accept("ALTER", "DOMAIN")):
+		return a.alterType(p)
+	case a.postgres && (p.accept("DROP", "TYPE") || p.accept("DROP", "DOMAIN")):
+		return a.dropTypes(p)
+	}
+	return nil
+}
+
+// table returns the named table, or nil if it does not exist.
+func (a *applier) table(schema, name string) *sqlgen.Table {
+	schema = a.schemaName(schema)
+	for _, t := range a.schema.Tables {
+		if t.Schema == schema && t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// schemaName returns the schema of a name qualified by schema, defaulting to
+// the schema being built.
+func (a *applier) schemaName(schema string) string {
+	if schema == "" {
+		return a.schema.Name
+	}
+	return schema
+}
+
+// createTable adds the table of a CREATE TABLE statement. Partitions are left
+// out, as they are used through their partitioned table.
+func (a *applier) createTable(p *parser, kind sqlgen.TableKind) error {
+	ifNotExists := p.accept("IF", "NOT", "EXISTS")
+	schema, name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	// CREATE TABLE ... LIKE copies the whole table on MySQL
+	likeAll := !a.postgres && (p.accept("LIKE") || p.accept("(", "LIKE"))
+	if !likeAll && !p.peek().is("(") {
+		// CREATE TABLE ... AS, ... OF type and ... PARTITION OF carry no
+		// column definitions
+		return nil
+	}
+	if a.table(schema, name) != nil {
+		if ifNotExists {
+			return nil
+		}
+		return p.errorf("table %s already exists", name)
+	}
+
+	t := &sqlgen.Table{Schema: a.schemaName(schema), Name: name, Kind: kind}
+	if likeAll {
+		if err := a.like(p, t, likeOptions{defaults: true, identity: true, generated: true, indexes: true}); err != nil {
+			return err
+		}
+		a.schema.Tables = append(a.schema.Tables, t)
+		return nil
+	}
+	p.next()
+	for !p.peek().is(")") {
+		if err := a.tableElement(p, t); err != nil {
+			return err
+		}
+		if !p.accept(",") {
+			break
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return err
+	}
+	// partitioning by MySQL keeps an ordinary table
+	for a.postgres && !p.done() {
+		if p.accept("INHERITS") {
+			if err := a.inherits(p, t); err != nil {
+				return err
+			}
+			continue
+		}
+		if p.accept("PARTITION", "BY") {
+			t.Kind = sqlgen.KindPartitionedTable
+			break
+		}
+		p.next()
+	}
+	a.schema.Tables = append(a.schema.Tables, t)
+	return nil
+}
+
+func (a *applier) tableElement(p *parser, t *sqlgen.Table) error {
+	if a.atConstraint(p) {
+		return a.constraint(p, t)
+	}
+	if p.accept("LIKE") {
+		return a.like(p, t, likeOptions{})
+	}
+	_, err := a.column(p, t, len(t.Columns))
+	return err
+}
+
+// likeOptions are the parts of a table copied by LIKE besides its columns,
+// their types and NOT NULL.
+type likeOptions struct {
+	defaults, identity, generated, indexes bool
+}
+
+// like copies the columns of the table named by a LIKE clause to t, with
+// the parts chosen by opts and its INCLUDING and EXCLUDING options.
+func (a *applier) like(p *parser, t *sqlgen.Table, opts likeOptions) error {
+	schema, name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	src := a.table(schema, name)
+	if src == nil {
+		return p.errorf("table %s does not exist", name)
+	}
+	for p.peek().is("INCLUDING") || p.peek().is("EXCLUDING") {
+		include := p.next().is("INCLUDING")
+		switch option := p.next(); {
+		case option.is("ALL"):
+			opts = likeOptions{defaults: include, identity: include, generated: include, indexes: include}
+		case option.is("DEFAULTS"):
+			opts.defaults = include
+		case option.is("IDENTITY"):
+			opts.identity = include
+		case option.is("GENERATED"):
+			opts.generated = include
+		case option.is("INDEXES"):
+			opts.indexes = include
+		}
+	}
+
+	for _, c := range src.Columns {
+		if t.Column(c.Name) != nil {
+			return p.errorf("column %s of table %s already exists", c.Name, t.Name)
+		}
+		copied := *c
+		if !opts.defaults && copied.Default != nil {
+			// a serial column without its nextval() default is not
+			// assigned by the database
+			copied.AutoIncrement = false
+			copied.Default = nil
+		}
+		if !opts.identity && copied.Identity != "" {
+			copied.AutoIncrement = false
+			copied.Identity = ""
+		}
+		if !opts.generated {
+			copied.Generated = false
+		}
+		t.Columns = append(t.Columns, &copied)
+	}
+	renumber(t)
+	if !opts.indexes {
+		return nil
+	}
+	if src.PrimaryKey != nil {
+		if err := a.setPrimaryKey(p, t, "", slices.Clone(src.PrimaryKey.Columns)); err != nil {
+			return err
+		}
+	}
+	for _, index := range src.Indexes {
+		// PostgreSQL names the copies after the new table
+		name, suffix := index.Name, "idx"
+		if a.postgres {
+			name = ""
+		}
+		if index.Unique {
+			suffix = "key"
+		}
+		a.addIndex(t, name, slices.Clone(index.Columns), index.Unique, suffix)
+	}
+	return nil
+}
+
+// inherits applies the INHERITS clause of a CREATE TABLE statement. The
+// columns of the parents come first, merged with the ones of the same name;
+// identities, keys and indexes are not inherited.
+func (a *applier) inherits(p *parser, t *sqlgen.Table) error {
+	if err := p.expect("("); err != nil {
+		return err
+	}
+	var columns []*sqlgen.Column
+	for {
+		schema, name, err := p.qualifiedName()
+		if err != nil {
+			return err
+		}
+		parent := a.table(schema, name)
+		if parent == nil {
+			return p.errorf("table %s does not exist", name)
+		}
+		for _, c := range parent.Columns {
+			if i := columnIndex(columns, c.Name); i >= 0 {
+				columns[i].Nullable = columns[i].Nullable && c.Nullable
+				continue
+			}
+			copied := *c
+			if copied.Identity != "" {
+				copied.AutoIncrement = false
+				copied.Identity = ""
+			}
+			columns = append(columns, &copied)
+		}
+		if !p.accept(",") {
+			break
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return err
+	}
+
+	for _, c := range t.Columns {
+		i := columnIndex(columns, c.Name)
+		if i < 0 {
+			columns = append(columns, c)
+			continue
+		}
+		c.Nullable = c.Nullable && columns[i].Nullable
+		if c.Default == nil {
+			c.Default, c.AutoIncrement = columns[i].Default, columns[i].AutoIncrement
+		}
+		columns[i] = c
+	}
+	t.Columns = columns
+	renumber(t)
+	if t.PrimaryKey != nil {
+		for _, c := range t.ColumnsByName(t.PrimaryKey.Columns) {
+			c.Nullable = false
+		}
+	}
+	return nil
+}
+
+// columnIndex returns the index of the column named name in columns, or -1.
+func columnIndex(columns []*sqlgen.Column, name string) int {
+	return slices.IndexFunc(columns, func(c *sqlgen.Column) bool { return c.Name == name })
+}
+
+// atConstraint reports whether a table constraint follows rather than a
+// column definition.
+func (a *applier) atConstraint(p *parser) bool {
+	for _, kw := range []string{"CONSTRAINT", "PRIMARY", "UNIQUE", "FOREIGN", "CHECK", "EXCLUDE"} {
+		if p.peek().is(kw) {
+			return true
+		}
+	}
+	if a.postgres {
+		// key and index are valid column names on PostgreSQL
+		return false
+	}
+	for _, kw := range []string{"KEY", "INDEX", "FULLTEXT", "SPATIAL"} {
+		if p.peek().is(kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// columnKeywords start the constraints and options following a column type.
+var columnKeywords = map[string]bool{
+	"NOT": true, "NULL": true, "DEFAULT": true, "PRIMARY": true, "UNIQUE": true,
+	"KEY": true, "REFERENCES": true, "CHECK": true, "CONSTRAINT": true,
+	"AUTO_INCREMENT": true, "GENERATED": true, "AS": true, "COLLATE": true,
+	"COMMENT": true, "ON": true, "CHARSET": true, "VISIBLE": true,
+	"INVISIBLE": true, "STORAGE": true, "COMPRESSION": true, "SRID": true,
+	"COLUMN_FORMAT": true, "DEFERRABLE": true, "INITIALLY": true, "FIRST": true,
+	"AFTER": true,
+}
+
+// atColumnKeyword reports whether the current token starts a column
+// constraint or option.
+func atColumnKeyword(p *parser) bool {
+	t := p.peek()
+	if t.kind != tokenIdent {
+		return false
+	}
+	return columnKeywords[strings.ToUpper(t.text)] || t.is("CHARACTER") && p.peekAt(1).is("SET")
+}
+
+// column inserts the column defined by the following tokens into the columns
+// of t at index at, unless FIRST or AFTER places it elsewhere.
+func (a *applier) column(p *parser, t *sqlgen.Table, at int) (*sqlgen.Column, error) {
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	if t.Column(name) != nil {
+		return nil, p.errorf("column %s of table %s already exists", name, t.Name)
+	}
+	typeTokens := p.collect(func(token) bool { return atColumnKeyword(p) })
+	if len(typeTokens) == 0 {
+		return nil, p.errorf("missing type of column %s", name)
+	}
+	dataType, serial, unsigned := a.columnType(typeTokens)
+	c := &sqlgen.Column{Name: name, DataType: dataType, Nullable: true, Unsigned: unsigned}
+	t.Columns = slices.Insert(t.Columns, at, c)
+	renumber(t)
+	if serial {
+		c.AutoIncrement = true
+		c.Nullable = false
+		if a.postgres {
+			def := fmt.Sprintf("nextval('%s_%s_seq'::regclass)", t.Name, c.Name)
+			c.Default = &def
+		} else {
+			// SERIAL is BIGINT UNSIGNED NOT NULL AUTO_INCREMENT UNIQUE
+			a.addIndex(t, "", []string{c.Name}, true, "key")
+		}
+	}
+	if err := a.columnConstraints(p, t, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// columnConstraints applies the constraints and options following a column
+// type.
+func (a *applier) columnConstraints(p *parser, t *sqlgen.Table, c *sqlgen.Column) error {
+	for !p.done() && !p.peek().is(",") && !p.peek().is(")") {
+		var name string
+		if p.accept("CONSTRAINT") {
+			var err error
+			if name, err = p.ident(); err != nil {
+				return err
+			}
+		}
+		switch {
+		case p.accept("NOT", "NULL"):
+			c.Nullable = false
+		case p.accept("NULL"):
+			c.Nullable = true
+		case p.accept("DEFAULT"):
+			a.setDefault(c, p.expression())
+		case p.accept("PRIMARY", "KEY"), !a.postgres && p.accept("KEY"):
+			if err := a.setPrimaryKey(p, t, name, []string{c.Name}); err != nil {
+				return err
+			}
+		case p.accept("UNIQUE"):
+			p.accept("KEY")
+			a.addIndex(t, name, []string{c.Name}, true, "key")
+		case p.accept("REFERENCES"):
+			if err := a.references(p, t, name, []string{c.Name}); err != nil {
+				return err
+			}
+		case p.accept("CHECK"):
+			if err := p.skipGroup(); err != nil {
+				return err
+			}
+			p.accept("NO", "INHERIT")
+			_ = p.accept("NOT", "ENFORCED") || p.accept("ENFORCED")
+		case p.accept("AUTO_INCREMENT"):
+			c.AutoIncrement = true
+		case p.accept("GENERATED"):
+			if err := a.generated(p, c); err != nil {
+				return err
+			}
+		case p.accept("AS"):
+			if err := generatedExpression(p, c); err != nil {
+				return err
+			}
+		case p.accept("ON", "UPDATE"):
+			p.expression()
+		case p.accept("COLLATE"):
+			if _, _, err := p.qualifiedName(); err != nil {
+				return err
+			}
+		case p.accept("CHARACTER", "SET"), p.accept("CHARSET"), p.accept("COMMENT"),
+			p.accept("STORAGE"), p.accept("COMPRESSION"), p.accept("COLUMN_FORMAT"), p.accept("SRID"):
+			p.next()
+		case !a.postgres && p.accept("FIRST"):
+			moveColumn(t, c, 0)
+		case !a.postgres && p.accept("AFTER"):
+			name, err := p.ident()
+			if err != nil {
+				return err
+			}
+			after := slices.IndexFunc(t.Columns, func(col *sqlgen.Column) bool { return col.Name == name })
+			if after < 0 {
+				return p.errorf("column %s of table %s does not exist", name, t.Name)
+			}
+			moveColumn(t, c, after+1)
+		case p.accept("VISIBLE"), p.accept("INVISIBLE"), p.accept("DEFERRABLE"), p.accept("NOT", "DEFERRABLE"),
+			p.accept("INITIALLY", "DEFERRED"), p.accept("INITIALLY", "IMMEDIATE"):
+		default:
+			return p.errorf("unexpected %s in definition of column %s", p.describe(), c.Name)
+		}
+	}
+	return nil
+}
+
+// generated applies a GENERATED clause: an identity or a generated column.
+func (a *applier) generated(p *parser, c *sqlgen.Column) error {
+	identity := sqlgen.IdentityAlways
+	if !p.accept("ALWAYS") && p.accept("BY", "DEFAULT") {
+		identity = sqlgen.IdentityByDefault
+	}
+	p.accept("ON", "NULL")
+	if err := p.expect("AS"); err != nil {
+		return err
+	}
+	if p.accept("IDENTITY") {
+		c.AutoIncrement = true
+		c.Identity = identity
+		c.Nullable = false
+		return p.skipGroup()
+	}
+	return generatedExpression(p, c)
+}
+
+// generatedExpression applies the expression and storage of a generated
+// column.
+func generatedExpression(p *parser, c *sqlgen.Column) error {
+	if err := p.skipGroup(); err != nil {
+		return err
+	}
+	_ = p.accept("STORED") || p.accept("VIRTUAL") || p.accept("PERSISTENT")
+	c.Generated = true
+	return nil
+}
+
+// expression consumes a default expression and returns it as SQL text.
+func (p *parser) expression() string {
+	start := p.pos
+	if p.peek().is("(") {
+		_ = p.skipGroup()
+	} else {
+		p.next()
+	}
+	p.collect(func(token) bool { return atColumnKeyword(p) })
+	return render(p.tokens[start:p.pos], false)
+}
+
+func (a *applier) setDefault(c *sqlgen.Column, expr string) {
+	if strings.EqualFold(expr, "NULL") {
+		c.Default = nil
+		return
+	}
+	c.Default = &expr
+	if a.postgres && strings.HasPrefix(expr, "nextval(") {
+		c.AutoIncrement = true
+	}
+}
+
+// constraint applies a table constraint or, on MySQL, an index definition.
+func (a *applier) constraint(p *parser, t *sqlgen.Table) error {
+	var name string
+	if p.accept("CONSTRAINT") && !a.atConstraint(p) {
+		var err error
+		if name, err = p.ident(); err != nil {
+			return err
+		}
+	}
+	switch {
+	case p.accept("PRIMARY", "KEY"):
+		a.skipIndexType(p)
+		cols, _, err := p.columnList()
+		if err != nil {
+			return err
+		}
+		if err := a.setPrimaryKey(p, t, name, cols); err != nil {
+			return err
+		}
+	case p.accept("UNIQUE"):
+		_ = p.accept("KEY") || p.accept("INDEX")
+		_ = p.accept("NULLS", "NOT", "DISTINCT") || p.accept("NULLS", "DISTINCT")
+		indexName, err := a.indexName(p)
+		if err != nil {
+			return err
+		}
+		if indexName != "" {
+			name = indexName
+		}
+		cols, ok, err := p.columnList()
+		if err != nil {
+			return err
+		}
+		if ok {
+			a.addIndex(t, name, cols, true, "key")
+		}
+	case p.accept("FOREIGN", "KEY"):
+		if !p.peek().is("(") {
+			// MySQL accepts an index name here; the constraint keeps its own
+			if _, err := p.ident(); err != nil {
+				return err
+			}
+		}
+		cols, _, err := p.columnList()
+		if err != nil {
+			return err
+		}
+		if err := p.expect("REFERENCES"); err != nil {
+			return err
+		}
+		if err := a.references(p, t, name, cols); err != nil {
+			return err
+		}
+	case p.accept("KEY"), p.accept("INDEX"):
+		name, err := a.indexName(p)
+		if err != nil {
+			return err
+		}
+		cols, ok, err := p.columnList()
+		if err != nil {
+			return err
+		}
+		if ok {
+			a.addIndex(t, name, cols, false, "idx")
+		}
+	}
+	// skip CHECK and EXCLUDE constraints, full-text indexes and index options
+	p.collect(nil)
+	return nil
+}
+
+// indexName consumes the optional name and index type preceding the column
+// list of a MySQL index definition.
+func (a *applier) indexName(p *parser) (string, error) {
+	var name string
+	if !p.peek().is("(") && !p.peek().is("USING") {
+		var err error
+		if name, err = p.ident(); err != nil {
+			return "", err
+		}
+	}
+	a.skipIndexType(p)
+	return name, nil
+}
+
+// skipIndexType consumes a USING BTREE or USING HASH clause.
+func (a *applier) skipIndexType(p *parser) {
+	if p.accept("USING") {
+		p.next()
+	}
+}
+
+func (a *applier) setPrimaryKey(p *parser, t *sqlgen.Table, name string, cols []string) error {
+	if t.PrimaryKey != nil {
+		return p.errorf("multiple primary keys for table %s", t.Name)
+	}
+	switch {
+	case !a.postgres:
+		name = "PRIMARY"
+	case name == "":
+		name = t.Name + "_pkey"
+	}
+	t.PrimaryKey = &sqlgen.PrimaryKey{Name: name, Columns: cols}
+	for _, c := range t.ColumnsByName(cols) {
+		c.Nullable = false
+	}
+	return nil
+}
+
+// addIndex adds an index named as the database would name it when name is
+// empty. suffix is the PostgreSQL suffix of generated names, "key" for unique
+// constraints and "idx" for indexes.
+func (a *applier) addIndex(t *sqlgen.Table, name string, cols []string, unique bool, suffix string) {
+	if name == "" {
+		if a.postgres {
+			name = a.uniqueName(t, fmt.Sprintf("%s_%s_%s", t.Name, strings.Join(cols, "_"), suffix))
+		} else {
+			name = a.uniqueName(t, cols[0])
+		}
+	}
+	t.Indexes = append(t.Indexes, &sqlgen.Index{Name: name, Columns: cols, Unique: unique})
+}
+
+// uniqueName returns base, numbered when an index of t already uses it.
+func (a *applier) uniqueName(t *sqlgen.Table, base string) string {
+	name := base
+	for n := 2; t.Index(name) != nil; n++ {
+		if a.postgres {
+			name = fmt.Sprintf("%s%d", base, n-1)
+		} else {
+			name = fmt.Sprintf("%s_%d", base, n)
+		}
+	}
+	return name
+}
+
+// references applies a REFERENCES clause of the given columns.
+func (a *applier) references(p *parser, t *sqlgen.Table, name string, cols []string) error {
+	schema, table, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	var refCols []string
+	if p.peek().is("(") {
+		if refCols, _, err = p.columnList(); err != nil {
+			return err
+		}
+	} else if ref := a.table(schema, table); ref != nil && ref.PrimaryKey != nil {
+		refCols = slices.Clone(ref.PrimaryKey.Columns)
+	}
+	if name == "" {
+		if a.postgres {
+			name = fmt.Sprintf("%s_%s_fkey", t.Name, strings.Join(cols, "_"))
+		} else {
+			name = fmt.Sprintf("%s_ibfk_%d", t.Name, len(t.ForeignKeys)+1)
+		}
+	}
+	t.ForeignKeys = append(t.ForeignKeys, &sqlgen.ForeignKey{
+		Name:              name,
+		Columns:           cols,
+		ReferencedSchema:  a.schemaName(schema),
+		ReferencedTable:   table,
+		ReferencedColumns: refCols,
+	})
+
+	for {
+		switch {
+		case p.accept("MATCH"):
+			p.next()
+		case p.accept("ON", "DELETE"), p.accept("ON", "UPDATE"):
+			switch {
+			case p.accept("NO", "ACTION"), p.accept("CASCADE"), p.accept("RESTRICT"), p.accept("SET", "DEFAULT"):
+			case p.accept("SET", "NULL"):
+				if err := p.skipGroup(); err != nil {
+					return err
+				}
+			default:
+				return p.errorf("unexpected %s in referential action", p.describe())
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+func (a *applier) createIndex(p *parser) error {
+	unique := p.accept("UNIQUE")
+	if err := p.expect("INDEX"); err != nil {
+		return err
+	}
+	p.accept("CONCURRENTLY")
+	ifNotExists := p.accept("IF", "NOT", "EXISTS")
+	var name string
+	if !p.peek().is("ON") {
+		var err error
+		if name, err = p.ident(); err != nil {
+			return err
+		}
+	}
+	a.skipIndexType(p)
+	if err := p.expect("ON"); err != nil {
+		return err
+	}
+	p.accept("ONLY")
+	schema, table, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	t := a.table(schema, table)
+	if t == nil {
+		return p.errorf("table %s does not exist", table)
+	}
+	a.skipIndexType(p)
+	cols, ok, err := p.columnList()
+	if err != nil {
+		return err
+	}
+	for !p.done() {
+		if p.next().is("WHERE") {
+			// partial indexes do not guarantee uniqueness of every row
+			return nil
+		}
+	}
+	if !ok {
+		// expression indexes have no columns to look up by
+		return nil
+	}
+	if name != "" && t.Index(name) != nil {
+		if ifNotExists {
+			return nil
+		}
+		return p.errorf("index %s already exists", name)
+	}
+	a.addIndex(t, name, cols, unique, "idx")
+	return nil
+}
+
+// finish resolves references to primary keys declared before the referenced
+// table and sorts the schema the way introspection does.
+func (a *applier) finish() {
+	for _, t := range a.schema.Tables {
+		for _, fk := range t.ForeignKeys {
+			if fk.ReferencedColumns != nil {
+				continue
+			}
+			if ref := a.table(fk.ReferencedSchema, fk.ReferencedTable); ref != nil && ref.PrimaryKey != nil {
+				fk.ReferencedColumns = slices.Clone(ref.PrimaryKey.Columns)
+			}
+		}
+		// drops may leave empty lists behind; the model uses nil for none
+		if len(t.Indexes) == 0 {
+			t.Indexes = nil
+		}
+		if len(t.ForeignKeys) == 0 {
+			t.ForeignKeys = nil
+		}
+		slices.SortStableFunc(t.Indexes, func(x, y *sqlgen.Index) int { return strings.Compare(x.Name, y.Name) })
+		slices.SortStableFunc(t.ForeignKeys, func(x, y *sqlgen.ForeignKey) int { return strings.Compare(x.Name, y.Name) })
+	}
+	slices.SortStableFunc(a.schema.Tables, func(x, y *sqlgen.Table) int {
+		return cmp.Or(strings.Compare(x.Schema, y.Schema), strings.Compare(x.Name, y.Name))
+	})
+}
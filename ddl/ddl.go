@@ -0,0 +1,66 @@
+// Package ddl reads schemas from CREATE TABLE, ALTER TABLE and CREATE INDEX
+// statements, so queries can be generated without a running database.
+package ddl
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/miyataka/sqlgen"
+)
+
+// Parse reads the DDL statements in src, written for dialect d, into a schema.
+// Statements that do not define tables or indexes are ignored.
+func Parse(d sqlgen.Dialect, src string) (*sqlgen.Schema, error) {
+	schema := &sqlgen.Schema{Name: defaultSchema(d)}
+	if err := Apply(schema, d, src); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// Apply applies the DDL statements in src to schema. Unqualified names refer
+// to tables of schema.Name. Only the types created in src are known.
+func Apply(schema *sqlgen.Schema, d sqlgen.Dialect, src string) error {
+	a := newApplier(schema, d)
+	if err := a.apply(src); err != nil {
+		return err
+	}
+	a.finish()
+	return nil
+}
+
+// defaultSchema returns the schema unqualified names belong to.
+func defaultSchema(d sqlgen.Dialect) string {
+	if d.Name() == "postgres" {
+		return "public"
+	}
+	return ""
+}
+
+// FileIntrospector reads the schema from DDL files instead of a database.
+type FileIntrospector struct {
+	Dialect sqlgen.Dialect
+	// Paths are the files to read, applied in order.
+	Paths []string
+}
+
+// Introspect applies the files in order and returns their tables, or with
+// opts.Schema set the ones of that schema. Unqualified names belong to the
+// default schema, public for PostgreSQL.
+func (i *FileIntrospector) Introspect(ctx context.Context, opts sqlgen.IntrospectOptions) (*sqlgen.Schema, error) {
+	schema := &sqlgen.Schema{Name: defaultSchema(i.Dialect)}
+	a := newApplier(schema, i.Dialect)
+	for _, path := range i.Paths {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := a.apply(string(src)); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	a.finish()
+	return sqlgen.StaticIntrospector{Schema: schema}.Introspect(ctx, opts)
+}
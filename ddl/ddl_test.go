@@ -0,0 +1,525 @@
+package ddl
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/miyataka/sqlgen"
+)
+
+func ptr(s string) *string { return &s }
+
+func TestParsePostgres(t *testing.T) {
+	schema, err := Parse(sqlgen.PostgresDialect{}, `
+	CREATE EXTENSION IF NOT EXISTS citext;
+	CREATE TYPE public.shipment_status AS ENUM ('new', 'sent');
+	CREATE DOMAIN positive_int AS int4 CHECK (VALUE > 0);
+	CREATE DOMAIN weight positive_int NOT NULL;
+
+	-- accounts own users
+	CREATE TABLE Users (
+		id SERIAL PRIMARY KEY,
+		name VARCHAR(100) NOT NULL,
+		email VARCHAR(100) UNIQUE,
+		"Nickname" text DEFAULT 'none'::text,
+		created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+	);
+
+	CREATE TABLE public.order_items (
+		order_id int,
+		item_id int,
+		quantity integer NOT NULL DEFAULT -1 CHECK (quantity <> 0),
+		PRIMARY KEY (order_id, item_id)
+	);
+
+	CREATE TABLE shipments (
+		id bigint GENERATED ALWAYS AS IDENTITY,
+		order_id int4 NOT NULL,
+		item_id int4 NOT NULL,
+		user_id integer REFERENCES users ON DELETE SET NULL,
+		key text,
+		status public.shipment_status NOT NULL,
+		weight weight,
+		CONSTRAINT shipments_order_item_fkey FOREIGN KEY (order_id, item_id) REFERENCES order_items (order_id, item_id)
+	);
+
+	ALTER TABLE ONLY shipments ADD CONSTRAINT shipments_pkey PRIMARY KEY (id);
+	ALTER TABLE shipments OWNER TO app, ADD COLUMN note text NOT NULL;
+	CREATE INDEX ON order_items (item_id);
+	CREATE UNIQUE INDEX order_items_item_id_quantity_key ON order_items USING btree (item_id, quantity DESC);
+	CREATE UNIQUE INDEX users_lower_name_key ON users (lower(name));
+	CREATE UNIQUE INDEX users_active_email_key ON users (email) WHERE email IS NOT NULL;
+	CREATE FUNCTION touch() RETURNS trigger AS $$ BEGIN NEW.updated_at = now(); RETURN NEW; END; $$ LANGUAGE plpgsql;
+	`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	want := &sqlgen.Schema{
+		Name: "public",
+		Tables: []*sqlgen.Table{
+			{
+				Schema: "public",
+				Name:   "order_items",
+				Columns: []*sqlgen.Column{
+					{Name: "order_id", OrdinalPosition: 1, DataType: "integer"},
+					{Name: "item_id", OrdinalPosition: 2, DataType: "integer"},
+					{Name: "quantity", OrdinalPosition: 3, DataType: "integer", Default: ptr("-1")},
+				},
+				PrimaryKey: &sqlgen.PrimaryKey{Name: "order_items_pkey", Columns: []string{"order_id", "item_id"}},
+				Indexes: []*sqlgen.Index{
+					{Name: "order_items_item_id_idx", Columns: []string{"item_id"}},
+					{Name: "order_items_item_id_quantity_key", Columns: []string{"item_id", "quantity"}, Unique: true},
+				},
+			},
+			{
+				Schema: "public",
+				Name:   "shipments",
+				Columns: []*sqlgen.Column{
+					{Name: "id", OrdinalPosition: 1, DataType: "bigint", AutoIncrement: true, Identity: sqlgen.IdentityAlways},
+					{Name: "order_id", OrdinalPosition: 2, DataType: "integer"},
+					{Name: "item_id", OrdinalPosition: 3, DataType: "integer"},
+					{Name: "user_id", OrdinalPosition: 4, DataType: "integer", Nullable: true},
+					{Name: "key", OrdinalPosition: 5, DataType: "text", Nullable: true},
+					{Name: "status", OrdinalPosition: 6, DataType: "USER-DEFINED"},
+					{Name: "weight", OrdinalPosition: 7, DataType: "integer", Nullable: true},
+					{Name: "note", OrdinalPosition: 8, DataType: "text"},
+				},
+				PrimaryKey: &sqlgen.PrimaryKey{Name: "shipments_pkey", Columns: []string{"id"}},
+				ForeignKeys: []*sqlgen.ForeignKey{
+					{
+						Name:              "shipments_order_item_fkey",
+						Columns:           []string{"order_id", "item_id"},
+						ReferencedSchema:  "public",
+						ReferencedTable:   "order_items",
+						ReferencedColumns: []string{"order_id", "item_id"},
+					},
+					{
+						Name:              "shipments_user_id_fkey",
+						Columns:           []string{"user_id"},
+						ReferencedSchema:  "public",
+						ReferencedTable:   "users",
+						ReferencedColumns: []string{"id"},
+					},
+				},
+			},
+			{
+				Schema: "public",
+				Name:   "users",
+				Columns: []*sqlgen.Column{
+					{Name: "id", OrdinalPosition: 1, DataType: "integer", Default: ptr("nextval('users_id_seq'::regclass)"), AutoIncrement: true},
+					{Name: "name", OrdinalPosition: 2, DataType: "character varying"},
+					{Name: "email", OrdinalPosition: 3, DataType: "character varying", Nullable: true},
+					{Name: "Nickname", OrdinalPosition: 4, DataType: "text", Nullable: true, Default: ptr("'none'::text")},
+					{Name: "created_at", OrdinalPosition: 5, DataType: "timestamp with time zone", Default: ptr("now()")},
+				},
+				PrimaryKey: &sqlgen.PrimaryKey{Name: "users_pkey", Columns: []string{"id"}},
+				Indexes: []*sqlgen.Index{
+					{Name: "users_email_key", Columns: []string{"email"}, Unique: true},
+				},
+			},
+		},
+	}
+	assertSchema(t, schema, want)
+}
+
+func TestParseMySQL(t *testing.T) {
+	schema, err := Parse(sqlgen.MySQLDialect{}, "# tables of the shop\n"+
+		"CREATE TABLE `Users` (\n"+
+		"  id INT UNSIGNED NOT NULL AUTO_INCREMENT,\n"+
+		"  email VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL COMMENT 'login',\n"+
+		"  active BOOLEAN NOT NULL DEFAULT TRUE,\n"+
+		"  updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,\n"+
+		"  PRIMARY KEY (id),\n"+
+		"  UNIQUE KEY (email),\n"+
+		"  KEY idx_active (active, updated_at)\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n"+
+		"CREATE TABLE posts (\n"+
+		"  id BIGINT PRIMARY KEY AUTO_INCREMENT,\n"+
+		"  user_id INT UNSIGNED NOT NULL,\n"+
+		"  title TEXT,\n"+
+		"  FOREIGN KEY (user_id) REFERENCES `Users` (id) ON DELETE CASCADE,\n"+
+		"  FULLTEXT KEY ft_title (title)\n"+
+		");\n"+
+		"ALTER TABLE posts ADD UNIQUE INDEX uq_title (title(100));\n"+
+		"CREATE INDEX idx_lower ON posts ((lower(title)));\n")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	want := &sqlgen.Schema{
+		Tables: []*sqlgen.Table{
+			{
+				Name: "Users",
+				Columns: []*sqlgen.Column{
+					{Name: "id", OrdinalPosition: 1, DataType: "int", AutoIncrement: true, Unsigned: true},
+					{Name: "email", OrdinalPosition: 2, DataType: "varchar"},
+					{Name: "active", OrdinalPosition: 3, DataType: "tinyint", Default: ptr("TRUE")},
+					{Name: "updated_at", OrdinalPosition: 4, DataType: "datetime", Nullable: true, Default: ptr("CURRENT_TIMESTAMP")},
+				},
+				PrimaryKey: &sqlgen.PrimaryKey{Name: "PRIMARY", Columns: []string{"id"}},
+				Indexes: []*sqlgen.Index{
+					{Name: "email", Columns: []string{"email"}, Unique: true},
+					{Name: "idx_active", Columns: []string{"active", "updated_at"}},
+				},
+			},
+			{
+				Name: "posts",
+				Columns: []*sqlgen.Column{
+					{Name: "id", OrdinalPosition: 1, DataType: "bigint", AutoIncrement: true},
+					{Name: "user_id", OrdinalPosition: 2, DataType: "int", Unsigned: true},
+					{Name: "title", OrdinalPosition: 3, DataType: "text", Nullable: true},
+				},
+				PrimaryKey: &sqlgen.PrimaryKey{Name: "PRIMARY", Columns: []string{"id"}},
+				Indexes: []*sqlgen.Index{
+					{Name: "uq_title", Columns: []string{"title"}, Unique: true},
+				},
+				ForeignKeys: []*sqlgen.ForeignKey{
+					{
+						Name:              "posts_ibfk_1",
+						Columns:           []string{"user_id"},
+						ReferencedTable:   "Users",
+						ReferencedColumns: []string{"id"},
+					},
+				},
+			},
+		},
+	}
+	assertSchema(t, schema, want)
+}
+
+func assertSchema(t *testing.T, got, want *sqlgen.Schema) {
+	t.Helper()
+	if got.Name != want.Name {
+		t.Errorf("expected schema name %q, got %q", want.Name, got.Name)
+	}
+	if len(got.Tables) != len(want.Tables) {
+		t.Fatalf("expected %d tables, got %d", len(want.Tables), len(got.Tables))
+	}
+	for i, table := range got.Tables {
+		if !reflect.DeepEqual(table, want.Tables[i]) {
+			t.Errorf("unexpected table %s:\n got: %s\nwant: %s", table.Name, describe(table), describe(want.Tables[i]))
+		}
+	}
+}
+
+// describe prints a table with its nested structs for test failures.
+func describe(t *sqlgen.Table) string {
+	var b strings.Builder
+	b.WriteString(t.Schema + "." + t.Name)
+	for _, c := range t.Columns {
+		def := "<nil>"
+		if c.Default != nil {
+			def = *c.Default
+		}
+		b.WriteString("\n  column " + strings.Join([]string{c.Name, c.DataType, def}, " "))
+		if c.Nullable {
+			b.WriteString(" nullable")
+		}
+		if c.AutoIncrement {
+			b.WriteString(" auto_increment")
+		}
+		if c.Unsigned {
+			b.WriteString(" unsigned")
+		}
+	}
+	if t.PrimaryKey != nil {
+		b.WriteString("\n  primary key " + t.PrimaryKey.Name + " " + strings.Join(t.PrimaryKey.Columns, ","))
+	}
+	for _, i := range t.Indexes {
+		b.WriteString("\n  index " + i.Name + " " + strings.Join(i.Columns, ","))
+		if i.Unique {
+			b.WriteString(" unique")
+		}
+	}
+	for _, fk := range t.ForeignKeys {
+		b.WriteString("\n  foreign key " + fk.Name + " " + strings.Join(fk.Columns, ",") + " -> " +
+			fk.ReferencedSchema + "." + fk.ReferencedTable + " " + strings.Join(fk.ReferencedColumns, ","))
+	}
+	return b.String()
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"duplicate table", "CREATE TABLE t (id int);\nCREATE TABLE t (id int);", "line 2: table t already exists"},
+		{"duplicate column", "CREATE TABLE t (id int, id int);", "line 1: column id of table t already exists"},
+		{"missing type", "CREATE TABLE t (\n  id\n);", "line 3: missing type of column id"},
+		{"unknown table", "ALTER TABLE t ADD COLUMN id int;", "line 1: table t does not exist"},
+		{"drop unknown table", "DROP TABLE t;", "line 1: table t does not exist"},
+		{"drop unknown column", "CREATE TABLE t (id int);\nALTER TABLE t DROP COLUMN name;", "line 2: column name of table t does not exist"},
+		{"two primary keys", "CREATE TABLE t (id int PRIMARY KEY, PRIMARY KEY (id));", "line 1: multiple primary keys for table t"},
+		{"unterminated string", "CREATE TABLE t (id int DEFAULT 'x);", "unterminated quoted text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(sqlgen.PostgresDialect{}, tt.src)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestParseIgnoresExistingObjects(t *testing.T) {
+	schema, err := Parse(sqlgen.PostgresDialect{}, `
+	CREATE TABLE t (id int);
+	CREATE TABLE IF NOT EXISTS t (other int);
+	ALTER TABLE t ADD COLUMN IF NOT EXISTS id int;
+	ALTER TABLE IF EXISTS missing ADD COLUMN id int;
+	CREATE TEMPORARY TABLE scratch (id int);
+	`)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(schema.Tables) != 1 || len(schema.Tables[0].Columns) != 1 {
+		t.Errorf("unexpected tables: %s", describe(schema.Tables[0]))
+	}
+}
+
+func TestParseCopiedColumns(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  sqlgen.Dialect
+		src      string
+		tables   []string
+		expected string
+	}{
+		{
+			name:    "postgres like",
+			dialect: sqlgen.PostgresDialect{},
+			src: `
+			CREATE TABLE a (id serial PRIMARY KEY, email text NOT NULL UNIQUE, note text DEFAULT 'none');
+			CREATE TABLE c (LIKE a, extra int);
+			`,
+			tables: []string{"c"},
+			expected: "public.c" +
+				"\n  column id integer <nil>" +
+				"\n  column email text <nil>" +
+				"\n  column note text <nil> nullable" +
+				"\n  column extra integer <nil> nullable",
+		},
+		{
+			name:    "postgres like including all",
+			dialect: sqlgen.PostgresDialect{},
+			src: `
+			CREATE TABLE a (id serial PRIMARY KEY, email text NOT NULL UNIQUE, note text DEFAULT 'none');
+			CREATE TABLE c (LIKE a INCLUDING ALL EXCLUDING INDEXES);
+			CREATE TABLE d (LIKE a INCLUDING INDEXES);
+			`,
+			tables: []string{"c", "d"},
+			expected: "public.c" +
+				"\n  column id integer nextval('a_id_seq'::regclass) auto_increment" +
+				"\n  column email text <nil>" +
+				"\n  column note text 'none' nullable" +
+				"\npublic.d" +
+				"\n  column id integer <nil>" +
+				"\n  column email text <nil>" +
+				"\n  column note text <nil> nullable" +
+				"\n  primary key d_pkey id" +
+				"\n  index d_email_key email unique",
+		},
+		{
+			name:    "postgres inherits",
+			dialect: sqlgen.PostgresDialect{},
+			src: `
+			CREATE TABLE a (id int GENERATED ALWAYS AS IDENTITY, x text NOT NULL, y int);
+			CREATE TABLE g (y int NOT NULL, z int, PRIMARY KEY (id)) INHERITS (a);
+			`,
+			tables: []string{"a", "g"},
+			expected: "public.a" +
+				"\n  column id integer <nil> auto_increment" +
+				"\n  column x text <nil>" +
+				"\n  column y integer <nil> nullable" +
+				"\npublic.g" +
+				"\n  column id integer <nil>" +
+				"\n  column x text <nil>" +
+				"\n  column y integer <nil>" +
+				"\n  column z integer <nil> nullable" +
+				"\n  primary key g_pkey id",
+		},
+		{
+			name:    "mysql like",
+			dialect: sqlgen.MySQLDialect{},
+			src: "CREATE TABLE a (id int AUTO_INCREMENT PRIMARY KEY, email varchar(100), UNIQUE KEY uq_email (email));\n" +
+				"CREATE TABLE c LIKE a;\n" +
+				"CREATE TABLE d (LIKE a);\n",
+			tables: []string{"c", "d"},
+			expected: ".c" +
+				"\n  column id int <nil> auto_increment" +
+				"\n  column email varchar <nil> nullable" +
+				"\n  primary key PRIMARY id" +
+				"\n  index uq_email email unique" +
+				"\n.d" +
+				"\n  column id int <nil> auto_increment" +
+				"\n  column email varchar <nil> nullable" +
+				"\n  primary key PRIMARY id" +
+				"\n  index uq_email email unique",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := Parse(tt.dialect, tt.src)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			var tables []string
+			for _, name := range tt.tables {
+				tables = append(tables, describe(schema.Table(name)))
+			}
+			if got := strings.Join(tables, "\n"); got != tt.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.expected, got)
+			}
+		})
+	}
+
+	if _, err := Parse(sqlgen.PostgresDialect{}, "CREATE TABLE c (LIKE missing);"); err == nil || !strings.Contains(err.Error(), "table missing does not exist") {
+		t.Errorf("expected an error for a missing table, got %v", err)
+	}
+}
+
+func TestParseTableKinds(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  sqlgen.Dialect
+		src      string
+		expected string
+	}{
+		{
+			name:    "postgres",
+			dialect: sqlgen.PostgresDialect{},
+			src: `
+			CREATE TABLE events (id int, at date, PRIMARY KEY (id, at)) PARTITION BY RANGE (at);
+			CREATE TABLE events_2024 PARTITION OF events FOR VALUES FROM ('2024-01-01') TO ('2025-01-01');
+			CREATE FOREIGN TABLE remote_users (id int, name text) SERVER remote OPTIONS (table_name 'users');
+			CREATE VIEW recent_events AS SELECT * FROM events;
+			CREATE TABLE logs (id int) WITH (fillfactor = 70);
+			`,
+			expected: "events:partitioned_table logs: remote_users:foreign_table",
+		},
+		{
+			name:     "mysql",
+			dialect:  sqlgen.MySQLDialect{},
+			src:      "CREATE TABLE events (id int, at date) PARTITION BY RANGE (YEAR(at)) (PARTITION p0 VALUES LESS THAN (2025));",
+			expected: "events:",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := Parse(tt.dialect, tt.src)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			var tables []string
+			for _, table := range schema.Tables {
+				tables = append(tables, table.Name+":"+string(table.Kind))
+			}
+			if got := strings.Join(tables, " "); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseGeneratedColumns(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  sqlgen.Dialect
+		src      string
+		expected string
+	}{
+		{
+			name:    "postgres",
+			dialect: sqlgen.PostgresDialect{},
+			src: `
+			CREATE TABLE orders (
+				id bigint GENERATED ALWAYS AS IDENTITY (START WITH 10),
+				ref int GENERATED BY DEFAULT AS IDENTITY,
+				price int NOT NULL,
+				total int GENERATED ALWAYS AS (price * 2) STORED,
+				legacy int GENERATED ALWAYS AS (price) STORED,
+				seq int GENERATED ALWAYS AS IDENTITY,
+				created_at timestamptz NOT NULL DEFAULT now()
+			);
+			ALTER TABLE orders ALTER COLUMN ref SET GENERATED ALWAYS;
+			ALTER TABLE orders ALTER COLUMN legacy DROP EXPRESSION;
+			ALTER TABLE orders ALTER COLUMN seq DROP IDENTITY IF EXISTS;
+			`,
+			expected: "id:identity=always ref:identity=always price: total:generated legacy: seq: created_at:default",
+		},
+		{
+			name:    "mysql",
+			dialect: sqlgen.MySQLDialect{},
+			src: "CREATE TABLE orders (\n" +
+				"  id int AUTO_INCREMENT PRIMARY KEY,\n" +
+				"  price int NOT NULL,\n" +
+				"  total int AS (price * 2),\n" +
+				"  doubled int GENERATED ALWAYS AS (price * 2) STORED,\n" +
+				"  created_at datetime DEFAULT CURRENT_TIMESTAMP\n" +
+				");",
+			expected: "id:auto_increment price: total:generated doubled:generated created_at:default",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := Parse(tt.dialect, tt.src)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			var cols []string
+			for _, c := range schema.Tables[0].Columns {
+				var kind string
+				switch {
+				case c.Identity != "":
+					kind = "identity=" + string(c.Identity)
+				case c.AutoIncrement:
+					kind = "auto_increment"
+				case c.Generated:
+					kind = "generated"
+				case c.Default != nil:
+					kind = "default"
+				}
+				cols = append(cols, c.Name+":"+kind)
+			}
+			if got := strings.Join(cols, " "); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestFileIntrospector(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "1.sql")
+	second := filepath.Join(dir, "2.sql")
+	if err := os.WriteFile(first, []byte("CREATE TABLE users (id serial PRIMARY KEY);\nCREATE TABLE logs (line text);"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(second, []byte("ALTER TABLE users ADD COLUMN name text;"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	introspector := &FileIntrospector{Dialect: sqlgen.PostgresDialect{}, Paths: []string{first, second}}
+	schema, err := introspector.Introspect(context.Background(), sqlgen.IntrospectOptions{SkipTables: []string{"logs"}})
+	if err != nil {
+		t.Fatalf("Introspect failed: %v", err)
+	}
+	if len(schema.Tables) != 1 || schema.Tables[0].Name != "users" {
+		t.Fatalf("unexpected tables: %v", schema.Tables)
+	}
+	if got := sqlgen.ColumnNames(schema.Tables[0].Columns); !reflect.DeepEqual(got, []string{"id", "name"}) {
+		t.Errorf("unexpected columns: %v", got)
+	}
+
+	introspector.Paths = []string{filepath.Join(dir, "missing.sql")}
+	if _, err := introspector.Introspect(context.Background(), sqlgen.IntrospectOptions{}); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
@@ -0,0 +1,181 @@
+package ddl
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	// tokenIdent is an unquoted identifier or keyword.
+	tokenIdent
+	// tokenQuotedIdent is an identifier in double quotes or backticks.
+	tokenQuotedIdent
+	tokenString
+	tokenNumber
+	// tokenSymbol is punctuation or an operator, e.g. "(" or "::".
+	tokenSymbol
+)
+
+type token struct {
+	kind tokenKind
+	// text is the token as written in the source.
+	text string
+	// value is the unquoted identifier or string content.
+	value string
+	// line is the 1-based line the token starts on.
+	line int
+}
+
+// is reports whether the token is the given keyword or symbol, ignoring case.
+func (t token) is(s string) bool {
+	return (t.kind == tokenIdent || t.kind == tokenSymbol) && strings.EqualFold(t.text, s)
+}
+
+// tokenize splits src into tokens, dropping whitespace and comments. With
+// mysql set, "#" starts a comment and backslashes escape characters in strings.
+func tokenize(src string, mysql bool) ([]token, error) {
+	var tokens []token
+	rs := []rune(src)
+	line, counted := 1, 0
+	emit := func(i int, t token) {
+		line += strings.Count(string(rs[counted:i]), "\n")
+		counted = i
+		t.line = line
+		tokens = append(tokens, t)
+	}
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '-' && i+1 < len(rs) && rs[i+1] == '-', mysql && r == '#':
+			for i < len(rs) && rs[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(rs) && rs[i+1] == '*':
+			end := indexRunes(rs, i+2, "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated comment")
+			}
+			i = end + 2
+		case r == '\'':
+			text, value, n, err := scanQuoted(rs[i:], '\'', mysql)
+			if err != nil {
+				return nil, err
+			}
+			emit(i, token{kind: tokenString, text: text, value: value})
+			i += n
+		case r == '"' || r == '`':
+			text, value, n, err := scanQuoted(rs[i:], r, false)
+			if err != nil {
+				return nil, err
+			}
+			emit(i, token{kind: tokenQuotedIdent, text: text, value: value})
+			i += n
+		case r == '$' && dollarTag(rs[i:]) != "":
+			tag := dollarTag(rs[i:])
+			n := len([]rune(tag))
+			end := indexRunes(rs, i+n, tag)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated dollar-quoted string")
+			}
+			emit(i, token{kind: tokenString, text: string(rs[i : end+n]), value: string(rs[i+n : end])})
+			i = end + n
+		case unicode.IsDigit(r):
+			j := i
+			for j < len(rs) && (unicode.IsDigit(rs[j]) || rs[j] == '.') {
+				j++
+			}
+			emit(i, token{kind: tokenNumber, text: string(rs[i:j]), value: string(rs[i:j])})
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(rs) && (unicode.IsLetter(rs[j]) || unicode.IsDigit(rs[j]) || rs[j] == '_' || rs[j] == '$') {
+				j++
+			}
+			emit(i, token{kind: tokenIdent, text: string(rs[i:j]), value: string(rs[i:j])})
+			i = j
+		case r == ':' && i+1 < len(rs) && rs[i+1] == ':':
+			emit(i, token{kind: tokenSymbol, text: "::", value: "::"})
+			i += 2
+		default:
+			emit(i, token{kind: tokenSymbol, text: string(r), value: string(r)})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+// scanQuoted scans a string or quoted identifier starting at rs[0]. A doubled
+// quote stands for itself; with backslash set a backslash escapes the next
+// character as in MySQL strings.
+func scanQuoted(rs []rune, quote rune, backslash bool) (text, value string, n int, err error) {
+	var b strings.Builder
+	for i := 1; i < len(rs); i++ {
+		switch {
+		case backslash && rs[i] == '\\' && i+1 < len(rs):
+			i++
+			b.WriteRune(rs[i])
+		case rs[i] == quote && i+1 < len(rs) && rs[i+1] == quote:
+			i++
+			b.WriteRune(quote)
+		case rs[i] == quote:
+			return string(rs[:i+1]), b.String(), i + 1, nil
+		default:
+			b.WriteRune(rs[i])
+		}
+	}
+	return "", "", 0, fmt.Errorf("unterminated quoted text %s", string(rs[:min(len(rs), 20)]))
+}
+
+// indexRunes returns the index of the first occurrence of sub in rs at or
+// after from, or -1 if there is none.
+func indexRunes(rs []rune, from int, sub string) int {
+	pattern := []rune(sub)
+	for i := from; i+len(pattern) <= len(rs); i++ {
+		if slices.Equal(rs[i:i+len(pattern)], pattern) {
+			return i
+		}
+	}
+	return -1
+}
+
+// dollarTag returns the opening tag of a PostgreSQL dollar-quoted string such
+// as "$$" or "$body$", or "" when rs does not start with one.
+func dollarTag(rs []rune) string {
+	for i := 1; i < len(rs); i++ {
+		switch {
+		case rs[i] == '$':
+			return string(rs[:i+1])
+		case !unicode.IsLetter(rs[i]) && !unicode.IsDigit(rs[i]) && rs[i] != '_':
+			return ""
+		case i == 1 && unicode.IsDigit(rs[i]):
+			// $1 is a parameter, not a tag
+			return ""
+		}
+	}
+	return ""
+}
+
+// splitStatements splits tokens into statements at top-level semicolons.
+func splitStatements(tokens []token) [][]token {
+	var stmts [][]token
+	start := 0
+	for i, t := range tokens {
+		if t.is(";") {
+			if i > start {
+				stmts = append(stmts, tokens[start:i])
+			}
+			start = i + 1
+		}
+	}
+	if start < len(tokens) {
+		stmts = append(stmts, tokens[start:])
+	}
+	return stmts
+}
@@ -0,0 +1,71 @@
+package ddl
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   string
+		mysql bool
+		want  []string
+	}{
+		{"keywords and symbols", "CREATE TABLE t(id int);", false, []string{"CREATE", "TABLE", "t", "(", "id", "int", ")", ";"}},
+		{"comments", "a -- line\n/* block\n */ b", false, []string{"a", "b"}},
+		{"mysql comment", "a # line\nb", true, []string{"a", "b"}},
+		{"quoted identifiers", "\"a\"\"b\" `c`", false, []string{"a\"b", "c"}},
+		{"strings", "'it''s' 'a\\'b'", true, []string{"it's", "a'b"}},
+		{"dollar quoted", "$body$ a; b $body$ $$x$$", false, []string{" a; b ", "x"}},
+		{"casts", "'1'::int", false, []string{"1", "::", "int"}},
+		{"numbers", "-1.5", false, []string{"-", "1.5"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, err := tokenize(tt.src, tt.mysql)
+			if err != nil {
+				t.Fatalf("tokenize failed: %v", err)
+			}
+			var got []string
+			for _, tok := range tokens {
+				got = append(got, tok.value)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestTokenizeLines(t *testing.T) {
+	tokens, err := tokenize("a\n'multi\nline'\n  b", false)
+	if err != nil {
+		t.Fatalf("tokenize failed: %v", err)
+	}
+	var got []int
+	for _, tok := range tokens {
+		got = append(got, tok.line)
+	}
+	if want := []int{1, 2, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected lines %v, got %v", want, got)
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	tokens, err := tokenize("a; ; b c;\nd", false)
+	if err != nil {
+		t.Fatalf("tokenize failed: %v", err)
+	}
+	var got [][]string
+	for _, stmt := range splitStatements(tokens) {
+		var words []string
+		for _, tok := range stmt {
+			words = append(words, tok.text)
+		}
+		got = append(got, words)
+	}
+	if want := [][]string{{"a"}, {"b", "c"}, {"d"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,120 @@
+package ddl
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/miyataka/sqlgen"
+)
+
+// MigrationsIntrospector reads the schema by replaying the up migrations of a
+// golang-migrate, goose or dbmate migrations directory in version order.
+type MigrationsIntrospector struct {
+	Dialect sqlgen.Dialect
+	Dir     string
+}
+
+// Introspect replays the up migrations in order, like
+// FileIntrospector.Introspect.
+func (i *MigrationsIntrospector) Introspect(ctx context.Context, opts sqlgen.IntrospectOptions) (*sqlgen.Schema, error) {
+	paths, err := migrationFiles(i.Dir)
+	if err != nil {
+		return nil, err
+	}
+	schema := &sqlgen.Schema{Name: defaultSchema(i.Dialect)}
+	a := newApplier(schema, i.Dialect)
+	for _, path := range paths {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := a.apply(upMigration(string(src))); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	a.finish()
+	return sqlgen.StaticIntrospector{Schema: schema}.Introspect(ctx, opts)
+}
+
+// migrationFiles returns the SQL migrations of dir ordered by version, the
+// number the file names start with. Down migrations of golang-migrate
+// (*.down.sql) are left out.
+func migrationFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	type migration struct {
+		version uint64
+		path    string
+	}
+	var migrations []migration
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".sql") || strings.HasSuffix(name, ".down.sql") {
+			continue
+		}
+		digits := strings.IndexFunc(name, func(r rune) bool { return r < '0' || r > '9' })
+		version, err := strconv.ParseUint(name[:digits], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s does not start with a version number", name)
+		}
+		migrations = append(migrations, migration{version: version, path: filepath.Join(dir, name)})
+	}
+	slices.SortFunc(migrations, func(x, y migration) int {
+		return cmp.Or(cmp.Compare(x.version, y.version), strings.Compare(x.path, y.path))
+	})
+
+	paths := make([]string, len(migrations))
+	for i, m := range migrations {
+		paths[i] = m.path
+	}
+	return paths, nil
+}
+
+// upMigration returns the up section of a goose (-- +goose Up) or dbmate
+// (-- migrate:up) migration, or src itself when it has no sections. Lines
+// outside the up section are blanked so that errors keep their line numbers.
+func upMigration(src string) string {
+	lines := strings.Split(src, "\n")
+	sections := false
+	up := false
+	for i, line := range lines {
+		if marker, isUp := sectionMarker(line); marker {
+			sections, up = true, isUp
+		}
+		if !up {
+			lines[i] = ""
+		}
+	}
+	if !sections {
+		return src
+	}
+	return strings.Join(lines, "\n")
+}
+
+// sectionMarker reports whether line starts a goose or dbmate section and
+// whether it is the up section.
+func sectionMarker(line string) (marker, up bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 || fields[0] != "--" {
+		return false, false
+	}
+	switch {
+	case fields[1] == "+goose" && len(fields) > 2 && strings.EqualFold(fields[2], "up"):
+		return true, true
+	case fields[1] == "+goose" && len(fields) > 2 && strings.EqualFold(fields[2], "down"):
+		return true, false
+	case fields[1] == "migrate:up":
+		return true, true
+	case fields[1] == "migrate:down":
+		return true, false
+	}
+	return false, false
+}
@@ -0,0 +1,112 @@
+package ddl
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/miyataka/sqlgen"
+)
+
+func writeMigrations(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestMigrationsIntrospector(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+	}{
+		{
+			name: "golang-migrate",
+			files: map[string]string{
+				"000001_create_users.up.sql":   "CREATE TABLE users (id serial PRIMARY KEY, name text);",
+				"000001_create_users.down.sql": "DROP TABLE users;",
+				"000002_add_email.up.sql":      "ALTER TABLE users ADD COLUMN email text;",
+				"000002_add_email.down.sql":    "ALTER TABLE users DROP COLUMN email;",
+				"000010_drop_name.up.sql":      "ALTER TABLE users DROP COLUMN name;",
+				"README.md":                    "not a migration",
+			},
+		},
+		{
+			name: "goose",
+			files: map[string]string{
+				"20240101000000_create_users.sql": "-- +goose Up\n-- +goose StatementBegin\nCREATE TABLE users (id serial PRIMARY KEY, name text);\n-- +goose StatementEnd\n\n-- +goose Down\nDROP TABLE users;\n",
+				"20240102000000_add_email.sql":    "-- +goose Up\nALTER TABLE users ADD COLUMN email text;\n-- +goose Down\nALTER TABLE users DROP COLUMN email;\n",
+				"20240103000000_drop_name.sql":    "-- +goose Up\nALTER TABLE users DROP COLUMN name;\n",
+			},
+		},
+		{
+			name: "dbmate",
+			files: map[string]string{
+				"20240101000000_create_users.sql": "-- migrate:up\nCREATE TABLE users (id serial PRIMARY KEY, name text);\n\n-- migrate:down\nDROP TABLE users;\n",
+				"20240102000000_add_email.sql":    "-- migrate:up transaction:false\nALTER TABLE users ADD COLUMN email text;\n-- migrate:down\n",
+				"20240103000000_drop_name.sql":    "-- migrate:up\nALTER TABLE users DROP COLUMN name;\n-- migrate:down\nALTER TABLE users ADD COLUMN name text;\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			introspector := &MigrationsIntrospector{Dialect: sqlgen.PostgresDialect{}, Dir: writeMigrations(t, tt.files)}
+			schema, err := introspector.Introspect(context.Background(), sqlgen.IntrospectOptions{})
+			if err != nil {
+				t.Fatalf("Introspect failed: %v", err)
+			}
+			users := schema.Table("users")
+			if users == nil {
+				t.Fatal("missing users table")
+			}
+			if got := sqlgen.ColumnNames(users.Columns); !reflect.DeepEqual(got, []string{"id", "email"}) {
+				t.Errorf("unexpected columns: %v", got)
+			}
+		})
+	}
+}
+
+func TestMigrationsTypes(t *testing.T) {
+	dir := writeMigrations(t, map[string]string{
+		"000001_create_types.up.sql":  "CREATE TYPE mood AS ENUM ('ok', 'sad');\nCREATE DOMAIN email AS citext;",
+		"000002_create_users.up.sql":  "CREATE TABLE users (id int PRIMARY KEY, mood mood, email email);",
+		"000003_rename_mood.up.sql":   "ALTER TYPE mood RENAME TO feeling;\nDROP DOMAIN email;",
+		"000004_add_feeling.up.sql":   "ALTER TABLE users ADD COLUMN feeling feeling, ADD COLUMN mood2 mood;",
+		"000004_add_feeling.down.sql": "ALTER TABLE users DROP COLUMN feeling, DROP COLUMN mood2;",
+	})
+	introspector := &MigrationsIntrospector{Dialect: sqlgen.PostgresDialect{}, Dir: dir}
+	schema, err := introspector.Introspect(context.Background(), sqlgen.IntrospectOptions{})
+	if err != nil {
+		t.Fatalf("Introspect failed: %v", err)
+	}
+	var got []string
+	for _, c := range schema.Table("users").Columns {
+		got = append(got, c.Name+" "+c.DataType)
+	}
+	want := []string{"id integer", "mood USER-DEFINED", "email citext", "feeling USER-DEFINED", "mood2 mood"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestMigrationsIntrospectorErrors(t *testing.T) {
+	dir := writeMigrations(t, map[string]string{"create_users.sql": "CREATE TABLE users (id int);"})
+	introspector := &MigrationsIntrospector{Dialect: sqlgen.PostgresDialect{}, Dir: dir}
+	if _, err := introspector.Introspect(context.Background(), sqlgen.IntrospectOptions{}); err == nil || !strings.Contains(err.Error(), "version number") {
+		t.Errorf("expected a version error, got %v", err)
+	}
+
+	dir = writeMigrations(t, map[string]string{"1_add.sql": "-- +goose Down\nDROP TABLE users;\n\n-- +goose Up\n\nALTER TABLE users ADD COLUMN id int;\n"})
+	introspector.Dir = dir
+	_, err := introspector.Introspect(context.Background(), sqlgen.IntrospectOptions{})
+	if want := filepath.Join(dir, "1_add.sql") + ": line 6: table users does not exist"; err == nil || err.Error() != want {
+		t.Errorf("expected error %q, got %v", want, err)
+	}
+}
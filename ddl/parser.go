@@ -0,0 +1,227 @@
+package ddl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parser is a cursor over the tokens of a single statement.
+type parser struct {
+	tokens []token
+	pos    int
+	// foldCase lower-cases unquoted identifiers as PostgreSQL does.
+	foldCase bool
+}
+
+func (p *parser) peek() token {
+	return p.peekAt(0)
+}
+
+// peekAt returns the token n positions ahead without consuming it.
+func (p *parser) peekAt(n int) token {
+	if p.pos+n < len(p.tokens) {
+		return p.tokens[p.pos+n]
+	}
+	return token{kind: tokenEOF}
+}
+
+func (p *parser) next() token {
+	t := p.peek()
+	if p.pos < len(p.tokens) {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+// accept consumes the given keywords if the next tokens match all of them.
+func (p *parser) accept(words ...string) bool {
+	for i, w := range words {
+		if !p.peekAt(i).is(w) {
+			return false
+		}
+	}
+	p.pos += len(words)
+	return true
+}
+
+// expect consumes the given keywords or fails.
+func (p *parser) expect(words ...string) error {
+	if !p.accept(words...) {
+		return p.errorf("expected %s, got %s", strings.Join(words, " "), p.describe())
+	}
+	return nil
+}
+
+// errorf returns an error located at the current token.
+func (p *parser) errorf(format string, args ...any) error {
+	line := 0
+	switch {
+	case p.pos < len(p.tokens):
+		line = p.tokens[p.pos].line
+	case len(p.tokens) > 0:
+		line = p.tokens[len(p.tokens)-1].line
+	}
+	return fmt.Errorf("line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+// describe returns the current token for error messages.
+func (p *parser) describe() string {
+	if p.done() {
+		return "end of statement"
+	}
+	return fmt.Sprintf("%q", p.peek().text)
+}
+
+// ident consumes an identifier.
+func (p *parser) ident() (string, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokenQuotedIdent:
+		p.pos++
+		return t.value, nil
+	case tokenIdent:
+		p.pos++
+		if p.foldCase {
+			return strings.ToLower(t.value), nil
+		}
+		return t.value, nil
+	}
+	return "", p.errorf("expected identifier, got %s", p.describe())
+}
+
+// qualifiedName consumes a name optionally qualified by a schema. The schema
+// is empty when the name is not qualified.
+func (p *parser) qualifiedName() (schema, name string, err error) {
+	name, err = p.ident()
+	if err != nil {
+		return "", "", err
+	}
+	if p.accept(".") {
+		schema = name
+		if name, err = p.ident(); err != nil {
+			return "", "", err
+		}
+	}
+	return schema, name, nil
+}
+
+// collect consumes tokens up to the first top-level ",", ")" or token for
+// which stop returns true, and returns them.
+func (p *parser) collect(stop func(token) bool) []token {
+	start := p.pos
+	depth := 0
+	for !p.done() {
+		t := p.peek()
+		switch {
+		case t.is("("):
+			depth++
+		case t.is(")") && depth == 0, t.is(",") && depth == 0:
+			return p.tokens[start:p.pos]
+		case t.is(")"):
+			depth--
+		case depth == 0 && stop != nil && stop(t):
+			return p.tokens[start:p.pos]
+		}
+		p.pos++
+	}
+	return p.tokens[start:p.pos]
+}
+
+// skipGroup consumes a parenthesized group if one follows.
+func (p *parser) skipGroup() error {
+	if !p.accept("(") {
+		return nil
+	}
+	p.collect(nil)
+	return p.expect(")")
+}
+
+// columnList consumes a parenthesized list of index columns. ok is false when
+// an entry is an expression rather than a plain column.
+func (p *parser) columnList() (cols []string, ok bool, err error) {
+	if err := p.expect("("); err != nil {
+		return nil, false, err
+	}
+	ok = true
+	for {
+		entry := &parser{tokens: p.collect(nil), foldCase: p.foldCase}
+		name, err := entry.ident()
+		if err != nil || !entry.plainColumnSuffix() {
+			ok = false
+		}
+		cols = append(cols, name)
+		if !p.accept(",") {
+			break
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, false, err
+	}
+	return cols, ok, nil
+}
+
+// plainColumnSuffix reports whether the remaining tokens of an index entry
+// only hold a prefix length, collation, operator class or sort order.
+func (p *parser) plainColumnSuffix() bool {
+	if p.accept("(") {
+		if p.next().kind != tokenNumber || !p.accept(")") {
+			return false
+		}
+	}
+	for !p.done() {
+		switch t := p.next(); t.kind {
+		case tokenIdent, tokenQuotedIdent:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// render joins tokens back into SQL text, e.g. "varchar(255)" or
+// "'x'::character varying". With lower set, keywords are lower-cased.
+func render(tokens []token, lower bool) string {
+	var b strings.Builder
+	for i, t := range tokens {
+		text := t.text
+		if lower && t.kind == tokenIdent {
+			text = strings.ToLower(text)
+		}
+		if i > 0 && spaceBetween(tokens[i-1], t) && !unarySign(tokens[:i]) {
+			b.WriteByte(' ')
+		}
+		b.WriteString(text)
+	}
+	return b.String()
+}
+
+// unarySign reports whether the last of tokens is a sign prefixing the next
+// token, as in "DEFAULT -1".
+func unarySign(tokens []token) bool {
+	last := tokens[len(tokens)-1]
+	if !last.is("-") && !last.is("+") {
+		return false
+	}
+	if len(tokens) == 1 {
+		return true
+	}
+	prev := tokens[len(tokens)-2]
+	return prev.kind == tokenSymbol && !prev.is(")")
+}
+
+// spaceBetween reports whether rendered SQL separates the two tokens.
+func spaceBetween(prev, t token) bool {
+	switch {
+	case prev.is("(") || prev.is("[") || prev.is(".") || prev.is("::"):
+		return false
+	case t.is(")") || t.is("[") || t.is("]") || t.is(",") || t.is(".") || t.is("::"):
+		return false
+	case t.is("("):
+		return prev.kind != tokenIdent && prev.kind != tokenQuotedIdent
+	}
+	return true
+}
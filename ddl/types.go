@@ -0,0 +1,183 @@
+package ddl
+
+import (
+	"slices"
+	"strings"
+)
+
+// postgresTypes maps PostgreSQL type aliases to the names reported by
+// information_schema.columns.data_type.
+var postgresTypes = map[string]string{
+	"int":         "integer",
+	"int4":        "integer",
+	"serial":      "integer",
+	"serial4":     "integer",
+	"int2":        "smallint",
+	"smallserial": "smallint",
+	"serial2":     "smallint",
+	"int8":        "bigint",
+	"bigserial":   "bigint",
+	"serial8":     "bigint",
+	"bool":        "boolean",
+	"varchar":     "character varying",
+	"char":        "character",
+	"bpchar":      "character",
+	"varbit":      "bit varying",
+	"float4":      "real",
+	"float8":      "double precision",
+	"float":       "double precision",
+	"decimal":     "numeric",
+	"timestamp":   "timestamp without time zone",
+	"timestamptz": "timestamp with time zone",
+	"time":        "time without time zone",
+	"timetz":      "time with time zone",
+}
+
+// mysqlTypes maps MySQL type aliases to the names reported by
+// INFORMATION_SCHEMA.COLUMNS.DATA_TYPE.
+var mysqlTypes = map[string]string{
+	"integer":           "int",
+	"bool":              "tinyint",
+	"boolean":           "tinyint",
+	"dec":               "decimal",
+	"fixed":             "decimal",
+	"numeric":           "decimal",
+	"real":              "double",
+	"double precision":  "double",
+	"character":         "char",
+	"character varying": "varchar",
+	"serial":            "bigint",
+}
+
+// serialTypes are the pseudo-types that declare an auto-incremented column.
+var serialTypes = map[string]bool{
+	"serial":      true,
+	"serial2":     true,
+	"serial4":     true,
+	"serial8":     true,
+	"smallserial": true,
+	"bigserial":   true,
+}
+
+// normalizeType returns the data type of a column declared with the given
+// type tokens, named as introspection reports it: without length, precision
+// or modifiers and with aliases resolved. serial reports whether the type is
+// a serial pseudo-type and unsigned whether it is a MySQL UNSIGNED or
+// ZEROFILL one.
+func normalizeType(postgres bool, tokens []token) (dataType string, serial, unsigned bool) {
+	var words []string
+	depth := 0
+	for _, t := range tokens {
+		switch {
+		case t.is("("):
+			depth++
+		case t.is(")"):
+			depth--
+		case depth > 0:
+		case t.is("[") || t.is("ARRAY"):
+			if postgres {
+				return "ARRAY", false, false
+			}
+		case t.is("."):
+			// schema-qualified type: keep the type name only
+			words = words[:0]
+		case t.is("UNSIGNED") || t.is("ZEROFILL"):
+			unsigned = true
+		case t.is("SIGNED"):
+		case t.kind == tokenIdent:
+			words = append(words, strings.ToLower(t.value))
+		case t.kind == tokenQuotedIdent:
+			words = append(words, t.value)
+		}
+	}
+	name := strings.Join(words, " ")
+	serial = serialTypes[name]
+	aliases := mysqlTypes
+	if postgres {
+		aliases = postgresTypes
+	} else if serial {
+		// SERIAL is BIGINT UNSIGNED NOT NULL AUTO_INCREMENT UNIQUE
+		unsigned = true
+	}
+	if alias, ok := aliases[name]; ok {
+		name = alias
+	}
+	return name, serial, unsigned
+}
+
+// columnType returns the data type of a column declared with the given type
+// tokens like normalizeType. Columns of enum, composite and range types are
+// USER-DEFINED and the ones of domains have the data type of the domain.
+func (a *applier) columnType(tokens []token) (dataType string, serial, unsigned bool) {
+	dataType, serial, unsigned = normalizeType(a.postgres, tokens)
+	if t, ok := a.types[dataType]; ok {
+		dataType = t
+	}
+	return dataType, serial, unsigned
+}
+
+// createType records the type of a CREATE TYPE statement. Introspection
+// reports the columns of all types created this way as USER-DEFINED.
+func (a *applier) createType(p *parser) error {
+	_, name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	a.types[name] = "USER-DEFINED"
+	return nil
+}
+
+// domainKeywords end the data type of a CREATE DOMAIN statement.
+var domainKeywords = []string{"COLLATE", "DEFAULT", "CONSTRAINT", "NOT", "NULL", "CHECK"}
+
+// createDomain records the data type of a CREATE DOMAIN statement.
+func (a *applier) createDomain(p *parser) error {
+	_, name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	p.accept("AS")
+	typeTokens := p.collect(func(t token) bool {
+		return slices.ContainsFunc(domainKeywords, func(kw string) bool { return t.is(kw) })
+	})
+	if len(typeTokens) == 0 {
+		return p.errorf("missing type of domain %s", name)
+	}
+	a.types[name], _, _ = a.columnType(typeTokens)
+	return nil
+}
+
+// alterType applies the RENAME TO of an ALTER TYPE or ALTER DOMAIN
+// statement. Columns keep the data type they were created with.
+func (a *applier) alterType(p *parser) error {
+	_, name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	dataType, ok := a.types[name]
+	if !ok || !p.accept("RENAME", "TO") {
+		return nil
+	}
+	newName, err := p.ident()
+	if err != nil {
+		return err
+	}
+	delete(a.types, name)
+	a.types[newName] = dataType
+	return nil
+}
+
+// dropTypes applies a DROP TYPE or DROP DOMAIN statement.
+func (a *applier) dropTypes(p *parser) error {
+	p.accept("IF", "EXISTS")
+	for {
+		_, name, err := p.qualifiedName()
+		if err != nil {
+			return err
+		}
+		delete(a.types, name)
+		if !p.accept(",") {
+			return nil
+		}
+	}
+}
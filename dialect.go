@@ -0,0 +1,106 @@
+package sqlgen
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Dialect describes the SQL syntax differences between databases.
+type Dialect interface {
+	// Name returns the database name, e.g. "postgres".
+	Name() string
+	// Placeholder returns the bind parameter for the n-th (1-based) argument.
+	Placeholder(n int) string
+	// QuoteIdent quotes an identifier unconditionally.
+	QuoteIdent(name string) string
+	// Reserved reports whether name is a key word that has to be quoted to
+	// be used as a table or column name, e.g. "order".
+	Reserved(name string) bool
+	// SupportsReturning reports whether INSERT and UPDATE can return the
+	// affected row with a RETURNING clause. Databases without it report the
+	// generated key through LAST_INSERT_ID() instead.
+	SupportsReturning() bool
+	// Upsert returns the clause appended to the VALUES list of an INSERT that
+	// turns it into an upsert. conflict lists the key columns and update the
+	// columns to overwrite; both are already quoted when required.
+	Upsert(conflict, update []string) string
+}
+
+// PostgresDialect is the Dialect of PostgreSQL.
+type PostgresDialect struct{}
+
+func (PostgresDialect) Name() string { return "postgres" }
+
+func (PostgresDialect) Placeholder(n int) string { return fmt.Sprintf("$%d", n) }
+
+func (PostgresDialect) QuoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func (PostgresDialect) Reserved(name string) bool { return postgresReserved[strings.ToLower(name)] }
+
+func (PostgresDialect) SupportsReturning() bool { return true }
+
+func (PostgresDialect) Upsert(conflict, update []string) string {
+	if len(update) == 0 {
+		// DO NOTHING would return no row on conflict; assigning a key column
+		// to itself returns the existing one.
+		update = conflict[:1]
+	}
+	sets := make([]string, len(update))
+	for i, c := range update {
+		sets[i] = fmt.Sprintf("%s = EXCLUDED.%s", c, c)
+	}
+	return fmt.Sprintf("ON CONFLICT (%s) DO UPDATE SET %s", strings.Join(conflict, ", "), strings.Join(sets, ", "))
+}
+
+// MySQLDialect is the Dialect of MySQL.
+type MySQLDialect struct {
+	// RowAlias makes upserts refer to the inserted values through a row
+	// alias (MySQL 8.0.19 and later) instead of the deprecated VALUES().
+	RowAlias bool
+}
+
+func (MySQLDialect) Name() string { return "mysql" }
+
+func (MySQLDialect) Placeholder(n int) string { return "?" }
+
+func (MySQLDialect) QuoteIdent(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func (MySQLDialect) Reserved(name string) bool { return mysqlReserved[strings.ToLower(name)] }
+
+func (MySQLDialect) SupportsReturning() bool { return false }
+
+func (d MySQLDialect) Upsert(conflict, update []string) string {
+	if len(update) == 0 {
+		// MySQL has no DO NOTHING; assigning a key column to itself is a no-op.
+		update = conflict[:1]
+	}
+	sets := make([]string, len(update))
+	for i, c := range update {
+		if d.RowAlias {
+			sets[i] = fmt.Sprintf("%s = new.%s", c, c)
+		} else {
+			sets[i] = fmt.Sprintf("%s = VALUES(%s)", c, c)
+		}
+	}
+	if d.RowAlias {
+		return "AS new ON DUPLICATE KEY UPDATE " + strings.Join(sets, ", ")
+	}
+	return "ON DUPLICATE KEY UPDATE " + strings.Join(sets, ", ")
+}
+
+var plainIdentifier = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// Ident returns name quoted for d when it cannot be used as is,
+// e.g. because it contains upper case letters or spaces or is a reserved
+// word.
+func Ident(d Dialect, name string) string {
+	if plainIdentifier.MatchString(name) && !d.Reserved(name) {
+		return name
+	}
+	return d.QuoteIdent(name)
+}
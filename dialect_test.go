@@ -0,0 +1,94 @@
+package sqlgen
+
+import "testing"
+
+func TestDialectPlaceholder(t *testing.T) {
+	if got := (PostgresDialect{}).Placeholder(3); got != "$3" {
+		t.Errorf("PostgresDialect.Placeholder(3) = %q; want %q", got, "$3")
+	}
+	if got := (MySQLDialect{}).Placeholder(3); got != "?" {
+		t.Errorf("MySQLDialect.Placeholder(3) = %q; want %q", got, "?")
+	}
+}
+
+func TestIdent(t *testing.T) {
+	testCases := []struct {
+		dialect  Dialect
+		input    string
+		expected string
+	}{
+		{PostgresDialect{}, "users", "users"},
+		{PostgresDialect{}, "user_id2", "user_id2"},
+		{PostgresDialect{}, "CamelCase", `"CamelCase"`},
+		{PostgresDialect{}, `odd"name`, `"odd""name"`},
+		{PostgresDialect{}, "user", `"user"`},
+		{PostgresDialect{}, "order", `"order"`},
+		{PostgresDialect{}, "values", "values"},
+		{MySQLDialect{}, "users", "users"},
+		{MySQLDialect{}, "first name", "`first name`"},
+		{MySQLDialect{}, "odd`name", "`odd``name`"},
+		{MySQLDialect{}, "user", "user"},
+		{MySQLDialect{}, "order", "`order`"},
+		{MySQLDialect{}, "values", "`values`"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.dialect.Name()+"/"+tc.input, func(t *testing.T) {
+			if got := Ident(tc.dialect, tc.input); got != tc.expected {
+				t.Errorf("Ident(%q) = %q; want %q", tc.input, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestDialectUpsert(t *testing.T) {
+	testCases := []struct {
+		name     string
+		dialect  Dialect
+		conflict []string
+		update   []string
+		expected string
+	}{
+		{
+			name:     "postgres",
+			dialect:  PostgresDialect{},
+			conflict: []string{"id"},
+			update:   []string{"name", "email"},
+			expected: "ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name, email = EXCLUDED.email",
+		},
+		{
+			name:     "postgres without update",
+			dialect:  PostgresDialect{},
+			conflict: []string{"order_id", "item_id"},
+			expected: "ON CONFLICT (order_id, item_id) DO UPDATE SET order_id = EXCLUDED.order_id",
+		},
+		{
+			name:     "mysql",
+			dialect:  MySQLDialect{},
+			conflict: []string{"id"},
+			update:   []string{"name", "email"},
+			expected: "ON DUPLICATE KEY UPDATE name = VALUES(name), email = VALUES(email)",
+		},
+		{
+			name:     "mysql with row alias",
+			dialect:  MySQLDialect{RowAlias: true},
+			conflict: []string{"id"},
+			update:   []string{"name", "email"},
+			expected: "AS new ON DUPLICATE KEY UPDATE name = new.name, email = new.email",
+		},
+		{
+			name:     "mysql without update",
+			dialect:  MySQLDialect{},
+			conflict: []string{"order_id", "item_id"},
+			expected: "ON DUPLICATE KEY UPDATE order_id = VALUES(order_id)",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.dialect.Upsert(tc.conflict, tc.update); got != tc.expected {
+				t.Errorf("Upsert() = %q; want %q", got, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package sqlgen
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// TableFilter selects tables by patterns on their names. A pattern is a glob
+// as in path.Match, e.g. audit_*, or a regular expression enclosed in
+// slashes, e.g. /events_\d{6}/. Either has to match the whole name. A
+// pattern of the form schema.table matches the schema too, e.g. audit.* or
+// public./tmp_.*/.
+type TableFilter struct {
+	include, exclude []tablePattern
+}
+
+// tablePattern matches the name and, if qualified, the schema of a table.
+type tablePattern struct {
+	schema func(string) bool
+	name   func(string) bool
+}
+
+// NewTableFilter returns a filter selecting the tables matching any of the
+// include patterns, or all tables if there are none, and none of the exclude
+// patterns.
+func NewTableFilter(include, exclude []string) (*TableFilter, error) {
+	f := &TableFilter{}
+	for _, p := range include {
+		tp, err := parseTablePattern(p)
+		if err != nil {
+			return nil, err
+		}
+		f.include = append(f.include, tp)
+	}
+	for _, p := range exclude {
+		tp, err := parseTablePattern(p)
+		if err != nil {
+			return nil, err
+		}
+		f.exclude = append(f.exclude, tp)
+	}
+	return f, nil
+}
+
+// Match reports whether the filter selects t.
+func (f *TableFilter) Match(t *Table) bool {
+	for _, p := range f.exclude {
+		if p.match(t) {
+			return false
+		}
+	}
+	if len(f.include) == 0 {
+		return true
+	}
+	for _, p := range f.include {
+		if p.match(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns a copy of schema with only the selected tables.
+func (f *TableFilter) Filter(schema *Schema) *Schema {
+	filtered := &Schema{Name: schema.Name}
+	for _, t := range schema.Tables {
+		if f.Match(t) {
+			filtered.Tables = append(filtered.Tables, t)
+		}
+	}
+	return filtered
+}
+
+func (p tablePattern) match(t *Table) bool {
+	return (p.schema == nil || p.schema(t.Schema)) && p.name(t.Name)
+}
+
+func parseTablePattern(s string) (tablePattern, error) {
+	schema, name, qualified := splitTablePattern(s)
+	var p tablePattern
+	var err error
+	if qualified {
+		if p.schema, err = parseNamePattern(schema); err != nil {
+			return tablePattern{}, fmt.Errorf("invalid table pattern %q: %w", s, err)
+		}
+	}
+	if p.name, err = parseNamePattern(name); err != nil {
+		return tablePattern{}, fmt.Errorf("invalid table pattern %q: %w", s, err)
+	}
+	return p, nil
+}
+
+// splitTablePattern splits a schema-qualified pattern at the dot separating
+// the schema from the table. A regular expression schema ends at the first
+// "/.".
+func splitTablePattern(s string) (schema, name string, qualified bool) {
+	if strings.HasPrefix(s, "/") {
+		if i := strings.Index(s[1:], "/."); i >= 0 {
+			return s[:i+2], s[i+3:], true
+		}
+		return "", s, false
+	}
+	if schema, name, ok := strings.Cut(s, "."); ok {
+		return schema, name, true
+	}
+	return "", s, false
+}
+
+// parseNamePattern returns the matcher of a glob or of a regular expression
+// enclosed in slashes.
+func parseNamePattern(s string) (func(string) bool, error) {
+	if len(s) >= 2 && strings.HasPrefix(s, "/") && strings.HasSuffix(s, "/") {
+		re, err := regexp.Compile("^(?:" + s[1:len(s)-1] + ")$")
+		if err != nil {
+			return nil, err
+		}
+		return re.MatchString, nil
+	}
+	if _, err := path.Match(s, ""); err != nil {
+		return nil, err
+	}
+	return func(name string) bool {
+		ok, _ := path.Match(s, name)
+		return ok
+	}, nil
+}
@@ -0,0 +1,54 @@
+package sqlgen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTableFilter(t *testing.T) {
+	schema := &Schema{Tables: []*Table{
+		{Schema: "public", Name: "users"},
+		{Schema: "public", Name: "audit_logs"},
+		{Schema: "public", Name: "events_202401"},
+		{Schema: "public", Name: "events_archive"},
+		{Schema: "audit", Name: "users"},
+	}}
+
+	tests := []struct {
+		name     string
+		include  []string
+		exclude  []string
+		expected string
+	}{
+		{name: "all", expected: "public.users public.audit_logs public.events_202401 public.events_archive audit.users"},
+		{name: "glob", exclude: []string{"audit_*", "events_*"}, expected: "public.users audit.users"},
+		{name: "regexp", exclude: []string{`/events_\d+/`}, expected: "public.users public.audit_logs public.events_archive audit.users"},
+		{name: "regexp matches the whole name", include: []string{"/events/"}, expected: ""},
+		{name: "include", include: []string{"users", "events_?????*"}, exclude: []string{"*archive"}, expected: "public.users public.events_202401 audit.users"},
+		{name: "schema-qualified", exclude: []string{"audit.*"}, expected: "public.users public.audit_logs public.events_202401 public.events_archive"},
+		{name: "schema-qualified regexp", include: []string{"/pub.*/./.*s/"}, expected: "public.users public.audit_logs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewTableFilter(tt.include, tt.exclude)
+			if err != nil {
+				t.Fatalf("NewTableFilter failed: %v", err)
+			}
+			var names []string
+			for _, table := range f.Filter(schema).Tables {
+				names = append(names, table.Schema+"."+table.Name)
+			}
+			if got := strings.Join(names, " "); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestTableFilterInvalidPattern(t *testing.T) {
+	for _, pattern := range []string{"users[", "/(/", "public./a[/"} {
+		if _, err := NewTableFilter(nil, []string{pattern}); err == nil {
+			t.Errorf("expected error for pattern %q", pattern)
+		}
+	}
+}
@@ -0,0 +1,686 @@
+package sqlgen
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Action is a kind of generated query.
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpsert Action = "upsert"
+	ActionRead   Action = "read"
+	ActionLookup Action = "lookup"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+	ActionList   Action = "list"
+	// ActionListByFK lists the rows referencing a parent row.
+	ActionListByFK Action = "list-by-fk"
+	// ActionCountByFK counts the rows referencing a parent row.
+	ActionCountByFK Action = "count-by-fk"
+)
+
+// Actions lists the supported actions in output order.
+var Actions = []Action{
+	ActionCreate, ActionUpsert, ActionRead, ActionLookup, ActionUpdate, ActionDelete,
+	ActionList, ActionListByFK, ActionCountByFK,
+}
+
+// DefaultActions lists the actions generated unless chosen otherwise.
+var DefaultActions = []Action{
+	ActionCreate, ActionUpsert, ActionRead, ActionLookup, ActionUpdate, ActionDelete,
+	ActionList, ActionListByFK,
+}
+
+// Writes reports whether the queries of a change rows.
+func (a Action) Writes() bool {
+	switch a {
+	case ActionCreate, ActionUpsert, ActionUpdate, ActionDelete:
+		return true
+	}
+	return false
+}
+
+// ParseActions parses a comma-separated list of action names.
+func ParseActions(s string) ([]Action, error) {
+	var actions []Action
+	for _, name := range strings.Split(s, ",") {
+		a := Action(strings.TrimSpace(name))
+		if !slices.Contains(Actions, a) {
+			return nil, fmt.Errorf("unknown action %q", a)
+		}
+		actions = append(actions, a)
+	}
+	return actions, nil
+}
+
+// Query is a generated SQL statement.
+type Query struct {
+	Table  *Table
+	Action Action
+	// Name is the query name used in sqlc annotations, e.g. "CreateUser".
+	Name string
+	// Cmd is the sqlc query command, e.g. ":one".
+	Cmd string
+	SQL string
+	// Params are the bind parameters of SQL in placeholder order.
+	Params []Param
+}
+
+// Param is a bind parameter of a query.
+type Param struct {
+	// Name is the snake_case name of the parameter, e.g. "user_id".
+	Name string
+	// Column is the column the parameter is written to or compared with.
+	// It is nil for LIMIT and OFFSET.
+	Column *Column
+}
+
+// columnParams returns the parameters bound to cols.
+func columnParams(cols []*Column) []Param {
+	params := make([]Param, len(cols))
+	for i, c := range cols {
+		params[i] = Param{Name: c.Name, Column: c}
+	}
+	return params
+}
+
+// Comment returns the sqlc annotation of the query.
+func (q Query) Comment() string {
+	return fmt.Sprintf("-- name: %s %s", q.Name, q.Cmd)
+}
+
+// Generator renders queries from the schema model.
+type Generator struct {
+	Dialect Dialect
+	// Actions selects the generated actions. Nil generates DefaultActions.
+	Actions []Action
+	// UpsertKeys maps table names or schema-qualified table names to the
+	// unique constraint or index an upsert of the table conflicts on. Tables
+	// not listed use the primary key.
+	UpsertKeys map[string]string
+	// InsertIdentity makes INSERT set the columns assigned by the database
+	// too, e.g. to copy rows along with their keys. Identity columns
+	// generated ALWAYS are inserted with OVERRIDING SYSTEM VALUE.
+	InsertIdentity bool
+	// SkipDefaults leaves the columns with a default value out of INSERT, so
+	// that the database fills them.
+	SkipDefaults bool
+	// QuoteIdentifiers quotes every table and column name instead of only
+	// the ones that require it.
+	QuoteIdentifiers bool
+	// NullPointers types the nullable columns of Models as pointers instead
+	// of sql.Null types, e.g. *string instead of sql.NullString.
+	NullPointers bool
+	// DefaultSchema is the schema whose tables are referenced by their name
+	// alone, e.g. "public". Tables of other schemas are schema-qualified and
+	// their query names start with the schema name, e.g. CreateAuditLog for
+	// audit.logs. Empty never qualifies table names.
+	DefaultSchema string
+	// Tables overrides the generation of single tables, keyed by table name
+	// or by schema-qualified table name.
+	Tables map[string]TableOptions
+	// QueryNames renames queries, keyed by their generated name, e.g.
+	// "GetUserByPk" to "GetUser".
+	QueryNames map[string]string
+	// Warnings receives a line for every table an action has to skip,
+	// e.g. DELETE on a table without primary key. Nil discards them.
+	Warnings io.Writer
+}
+
+// TableOptions overrides the generation of one table.
+type TableOptions struct {
+	// Actions replaces Generator.Actions for the table.
+	Actions []Action
+	// Name replaces the singular PascalCase name derived from the table
+	// name in query names, e.g. "User" for users.
+	Name string
+	// ReadOnlyColumns are never inserted or updated, e.g. columns filled by
+	// defaults or triggers.
+	ReadOnlyColumns []string
+	// HiddenColumns are never selected or returned, e.g. password hashes.
+	HiddenColumns []string
+}
+
+// Generate returns the queries for every table of the schema, grouped by
+// action in the order of Actions.
+func (g *Generator) Generate(schema *Schema) []Query {
+	var queries []Query
+	for _, a := range Actions {
+		for _, t := range schema.Tables {
+			if slices.Contains(g.actions(t), a) {
+				queries = append(queries, g.Queries(a, t)...)
+			}
+		}
+	}
+	for i, q := range queries {
+		if name, ok := g.QueryNames[q.Name]; ok {
+			queries[i].Name = name
+		}
+	}
+	return queries
+}
+
+// Queries returns the queries of action a for t. It returns nil when the
+// table does not support the action, e.g. SELECT by primary key on a table
+// without primary key or INSERT into a view.
+func (g *Generator) Queries(a Action, t *Table) []Query {
+	if a.Writes() && t.ReadOnly() {
+		return nil
+	}
+	var q Query
+	var ok bool
+	switch a {
+	case ActionLookup:
+		return g.Lookups(t)
+	case ActionList:
+		return g.List(t)
+	case ActionListByFK:
+		return g.ListByForeignKeys(t)
+	case ActionCountByFK:
+		return g.CountByForeignKeys(t)
+	case ActionCreate:
+		q, ok = g.Insert(t)
+	case ActionUpsert:
+		q, ok = g.Upsert(t)
+	case ActionRead:
+		q, ok = g.SelectByPk(t)
+	case ActionUpdate:
+		q, ok = g.UpdateByPk(t)
+	case ActionDelete:
+		q, ok = g.DeleteByPk(t)
+		if !ok {
+			g.warnf("skipping DELETE for table %s: no primary key", t.Name)
+		}
+	}
+	if !ok {
+		return nil
+	}
+	return []Query{q}
+}
+
+// Insert renders the INSERT statement of t. It reports false when the table
+// has no column to insert.
+func (g *Generator) Insert(t *Table) (Query, bool) {
+	cols := g.insertColumns(t)
+	if len(cols) == 0 {
+		return Query{}, false
+	}
+
+	sql := fmt.Sprintf("INSERT INTO %s %s", g.table(t), g.values(cols))
+	cmd := ":exec"
+	if g.Dialect.SupportsReturning() {
+		sql += g.returning(t)
+		cmd = ":one"
+	}
+	return Query{
+		Table:  t,
+		Action: ActionCreate,
+		Name:   "Create" + g.entityName(t),
+		Cmd:    cmd,
+		SQL:    sql + ";",
+		Params: columnParams(cols),
+	}, true
+}
+
+// Upsert renders the INSERT of t that updates the existing row when it
+// conflicts on the primary key or on the unique key chosen in UpsertKeys.
+// Key columns assigned by the database are inserted too, since the row to
+// update can only be identified by them, but generated columns never are. It
+// reports false when the table has no such key.
+func (g *Generator) Upsert(t *Table) (Query, bool) {
+	var key []string
+	if name, ok := lookupTable(g.UpsertKeys, t); ok {
+		index := t.Index(name)
+		if index == nil || !index.Unique {
+			g.warnf("skipping upsert for table %s: no unique key %s", t.Name, name)
+			return Query{}, false
+		}
+		key = index.Columns
+	} else if t.PrimaryKey != nil {
+		key = t.PrimaryKey.Columns
+	}
+	conflict := t.ColumnsByName(key)
+	if len(conflict) == 0 {
+		return Query{}, false
+	}
+
+	var cols, update []*Column
+	for _, c := range t.Columns {
+		isKey := slices.Contains(conflict, c)
+		if c.Generated || !isKey && (c.AutoIncrement || slices.Contains(g.tableOptions(t).ReadOnlyColumns, c.Name)) {
+			continue
+		}
+		cols = append(cols, c)
+		if !isKey {
+			update = append(update, c)
+		}
+	}
+	if len(cols) == 0 {
+		return Query{}, false
+	}
+
+	sql := fmt.Sprintf("INSERT INTO %s %s %s",
+		g.table(t), g.values(cols), g.Dialect.Upsert(g.idents(conflict), g.idents(update)))
+	cmd := ":exec"
+	if g.Dialect.SupportsReturning() {
+		sql += g.returning(t)
+		cmd = ":one"
+	}
+	return Query{
+		Table:  t,
+		Action: ActionUpsert,
+		Name:   "Upsert" + g.entityName(t),
+		Cmd:    cmd,
+		SQL:    sql + ";",
+		Params: columnParams(cols),
+	}, true
+}
+
+// SelectByPk renders the SELECT by primary key of t. It reports false when
+// the table has no primary key.
+func (g *Generator) SelectByPk(t *Table) (Query, bool) {
+	pkCols := t.PrimaryKeyColumns()
+	if len(pkCols) == 0 {
+		return Query{}, false
+	}
+	return Query{
+		Table:  t,
+		Action: ActionRead,
+		Name:   "Get" + g.entityName(t) + "ByPk",
+		Cmd:    ":one",
+		SQL: fmt.Sprintf("SELECT %s FROM %s WHERE %s;",
+			strings.Join(g.idents(g.selectColumns(t)), ", "), g.table(t), g.where(pkCols, 1)),
+		Params: columnParams(pkCols),
+	}, true
+}
+
+// Lookups renders a SELECT for every unique index of t, named after the
+// index columns, e.g. GetUserByEmail. Indexes on the primary key columns or
+// on the same columns as an earlier index are skipped.
+func (g *Generator) Lookups(t *Table) []Query {
+	seen := make(map[string]bool)
+	if t.PrimaryKey != nil {
+		seen[strings.Join(t.PrimaryKey.Columns, ",")] = true
+	}
+
+	var queries []Query
+	for _, index := range t.Indexes {
+		key := strings.Join(index.Columns, ",")
+		if !index.Unique || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		cols := t.ColumnsByName(index.Columns)
+		if cols == nil {
+			continue
+		}
+		queries = append(queries, Query{
+			Table:  t,
+			Action: ActionLookup,
+			Name:   "Get" + g.entityName(t) + "By" + pascalColumns(cols),
+			Cmd:    ":one",
+			SQL: fmt.Sprintf("SELECT %s FROM %s WHERE %s;",
+				strings.Join(g.idents(g.selectColumns(t)), ", "), g.table(t), g.where(cols, 1)),
+			Params: columnParams(cols),
+		})
+	}
+	return queries
+}
+
+// UpdateByPk renders the UPDATE by primary key of t, setting every writable
+// column outside the primary key. It reports false when the table has no primary
+// key or nothing to set.
+func (g *Generator) UpdateByPk(t *Table) (Query, bool) {
+	pkCols := t.PrimaryKeyColumns()
+	cols := g.writable(t, t.UpdateColumns())
+	if len(pkCols) == 0 || len(cols) == 0 {
+		return Query{}, false
+	}
+	sets := make([]string, len(cols))
+	for i, c := range cols {
+		sets[i] = fmt.Sprintf("%s = %s", g.ident(c.Name), g.Dialect.Placeholder(i+1))
+	}
+
+	sql := fmt.Sprintf("UPDATE %s SET %s WHERE %s",
+		g.table(t), strings.Join(sets, ", "), g.where(pkCols, len(cols)+1))
+	cmd := ":exec"
+	if g.Dialect.SupportsReturning() {
+		sql += g.returning(t)
+		cmd = ":one"
+	}
+	return Query{
+		Table:  t,
+		Action: ActionUpdate,
+		Name:   "Update" + g.entityName(t),
+		Cmd:    cmd,
+		SQL:    sql + ";",
+		Params: columnParams(append(slices.Clone(cols), pkCols...)),
+	}, true
+}
+
+// DeleteByPk renders the DELETE by primary key of t. It reports false when
+// the table has no primary key.
+func (g *Generator) DeleteByPk(t *Table) (Query, bool) {
+	pkCols := t.PrimaryKeyColumns()
+	if len(pkCols) == 0 {
+		return Query{}, false
+	}
+	return Query{
+		Table:  t,
+		Action: ActionDelete,
+		Name:   "Delete" + g.entityName(t),
+		Cmd:    ":execrows",
+		SQL:    fmt.Sprintf("DELETE FROM %s WHERE %s;", g.table(t), g.where(pkCols, 1)),
+		Params: columnParams(pkCols),
+	}, true
+}
+
+// List renders the paginated SELECTs of t ordered by primary key: one with
+// LIMIT and OFFSET and one continuing after a given key (keyset pagination).
+// It returns nil when the table has no primary key.
+func (g *Generator) List(t *Table) []Query {
+	pkCols := t.PrimaryKeyColumns()
+	if len(pkCols) == 0 {
+		return nil
+	}
+	cols := strings.Join(g.idents(g.selectColumns(t)), ", ")
+	orderBy := strings.Join(g.idents(pkCols), ", ")
+	name := "List" + g.entityPluralName(t)
+
+	after := make([]string, len(pkCols))
+	for i := range pkCols {
+		after[i] = g.Dialect.Placeholder(i + 1)
+	}
+	keyset := fmt.Sprintf("%s > %s", orderBy, strings.Join(after, ", "))
+	if len(pkCols) > 1 {
+		keyset = fmt.Sprintf("(%s) > (%s)", orderBy, strings.Join(after, ", "))
+	}
+
+	return []Query{
+		{
+			Table:  t,
+			Action: ActionList,
+			Name:   name,
+			Cmd:    ":many",
+			SQL: fmt.Sprintf("SELECT %s FROM %s ORDER BY %s LIMIT %s OFFSET %s;",
+				cols, g.table(t), orderBy, g.Dialect.Placeholder(1), g.Dialect.Placeholder(2)),
+			Params: []Param{{Name: "limit"}, {Name: "offset"}},
+		},
+		{
+			Table:  t,
+			Action: ActionList,
+			Name:   name + "After",
+			Cmd:    ":many",
+			SQL: fmt.Sprintf("SELECT %s FROM %s WHERE %s ORDER BY %s LIMIT %s;",
+				cols, g.table(t), keyset, orderBy, g.Dialect.Placeholder(len(pkCols)+1)),
+			Params: append(columnParams(pkCols), Param{Name: "limit"}),
+		},
+	}
+}
+
+// ListByForeignKeys renders a SELECT for every foreign key of t returning
+// the rows that reference a parent row, e.g. ListOrdersByUserId.
+func (g *Generator) ListByForeignKeys(t *Table) []Query {
+	var queries []Query
+	for _, cols := range g.foreignKeyColumns(t) {
+		sql := fmt.Sprintf("SELECT %s FROM %s WHERE %s",
+			strings.Join(g.idents(g.selectColumns(t)), ", "), g.table(t), g.where(cols, 1))
+		if pkCols := t.PrimaryKeyColumns(); len(pkCols) > 0 {
+			sql += " ORDER BY " + strings.Join(g.idents(pkCols), ", ")
+		}
+		queries = append(queries, Query{
+			Table:  t,
+			Action: ActionListByFK,
+			Name:   "List" + g.entityPluralName(t) + "By" + pascalColumns(cols),
+			Cmd:    ":many",
+			SQL:    sql + ";",
+			Params: columnParams(cols),
+		})
+	}
+	return queries
+}
+
+// CountByForeignKeys renders a COUNT for every foreign key of t counting the
+// rows that reference a parent row, e.g. CountOrdersByUserId.
+func (g *Generator) CountByForeignKeys(t *Table) []Query {
+	var queries []Query
+	for _, cols := range g.foreignKeyColumns(t) {
+		queries = append(queries, Query{
+			Table:  t,
+			Action: ActionCountByFK,
+			Name:   "Count" + g.entityPluralName(t) + "By" + pascalColumns(cols),
+			Cmd:    ":one",
+			SQL:    fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s;", g.table(t), g.where(cols, 1)),
+			Params: columnParams(cols),
+		})
+	}
+	return queries
+}
+
+// foreignKeyColumns returns the columns of every foreign key of t, leaving
+// out keys on the same columns as an earlier one.
+func (g *Generator) foreignKeyColumns(t *Table) [][]*Column {
+	seen := make(map[string]bool)
+	var result [][]*Column
+	for _, fk := range t.ForeignKeys {
+		key := strings.Join(fk.Columns, ",")
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if cols := t.ColumnsByName(fk.Columns); cols != nil {
+			result = append(result, cols)
+		}
+	}
+	return result
+}
+
+// where renders the conditions matching cols, numbering the placeholders
+// from start.
+func (g *Generator) where(cols []*Column, start int) string {
+	conds := make([]string, len(cols))
+	for i, c := range cols {
+		conds[i] = fmt.Sprintf("%s = %s", g.ident(c.Name), g.Dialect.Placeholder(start+i))
+	}
+	return strings.Join(conds, " AND ")
+}
+
+// insertColumns returns the columns set by the INSERT of t.
+func (g *Generator) insertColumns(t *Table) []*Column {
+	var cols []*Column
+	for _, c := range t.Columns {
+		switch {
+		case c.Generated:
+		case c.AutoIncrement && !g.InsertIdentity:
+		case !c.AutoIncrement && c.Default != nil && g.SkipDefaults:
+		default:
+			cols = append(cols, c)
+		}
+	}
+	return g.writable(t, cols)
+}
+
+// values renders the column list and the VALUES of an INSERT of cols,
+// overriding the system value when one of them is an identity column
+// generated ALWAYS.
+func (g *Generator) values(cols []*Column) string {
+	placeholders := make([]string, len(cols))
+	override := ""
+	for i, c := range cols {
+		placeholders[i] = g.Dialect.Placeholder(i + 1)
+		if c.Identity == IdentityAlways {
+			override = " OVERRIDING SYSTEM VALUE"
+		}
+	}
+	return fmt.Sprintf("(%s)%s VALUES (%s)",
+		strings.Join(g.idents(cols), ", "), override, strings.Join(placeholders, ", "))
+}
+
+// tableOptions returns the options of t in Tables.
+func (g *Generator) tableOptions(t *Table) TableOptions {
+	opts, _ := lookupTable(g.Tables, t)
+	return opts
+}
+
+// lookupTable returns the value of t in m, keyed by its schema-qualified
+// name or by its name.
+func lookupTable[V any](m map[string]V, t *Table) (V, bool) {
+	if t.Schema != "" {
+		if v, ok := m[t.Schema+"."+t.Name]; ok {
+			return v, true
+		}
+	}
+	v, ok := m[t.Name]
+	return v, ok
+}
+
+// qualified reports whether t is referenced by its schema-qualified name.
+func (g *Generator) qualified(t *Table) bool {
+	return g.DefaultSchema != "" && t.Schema != "" && t.Schema != g.DefaultSchema
+}
+
+// table renders the name of t, schema-qualified if it is outside
+// DefaultSchema.
+func (g *Generator) table(t *Table) string {
+	if g.qualified(t) {
+		return g.ident(t.Schema) + "." + g.ident(t.Name)
+	}
+	return g.ident(t.Name)
+}
+
+// actions returns the actions generated for t.
+func (g *Generator) actions(t *Table) []Action {
+	if actions := g.tableOptions(t).Actions; actions != nil {
+		return actions
+	}
+	if g.Actions != nil {
+		return g.Actions
+	}
+	return DefaultActions
+}
+
+// selectColumns returns the columns of t that are selected, leaving out the
+// hidden ones.
+func (g *Generator) selectColumns(t *Table) []*Column {
+	hidden := g.tableOptions(t).HiddenColumns
+	return slices.DeleteFunc(slices.Clone(t.Columns), func(c *Column) bool {
+		return slices.Contains(hidden, c.Name)
+	})
+}
+
+// writable returns cols without the read-only columns of t.
+func (g *Generator) writable(t *Table, cols []*Column) []*Column {
+	readOnly := g.tableOptions(t).ReadOnlyColumns
+	return slices.DeleteFunc(cols, func(c *Column) bool {
+		return slices.Contains(readOnly, c.Name)
+	})
+}
+
+// returning renders the RETURNING clause of t, listing the selected columns
+// when some are hidden.
+func (g *Generator) returning(t *Table) string {
+	if len(g.tableOptions(t).HiddenColumns) == 0 {
+		return " RETURNING *"
+	}
+	return " RETURNING " + strings.Join(g.idents(g.selectColumns(t)), ", ")
+}
+
+// entityName returns the singular PascalCase name of t used in query names.
+func (g *Generator) entityName(t *Table) string {
+	if name := g.tableOptions(t).Name; name != "" {
+		return name
+	}
+	return g.schemaPrefix(t) + SnakeToPascal(Singularize(t.Name))
+}
+
+// entityPluralName returns the plural PascalCase name of t used in names of
+// queries returning many rows.
+func (g *Generator) entityPluralName(t *Table) string {
+	if name := g.tableOptions(t).Name; name != "" {
+		return Pluralize(name)
+	}
+	return g.schemaPrefix(t) + SnakeToPascal(Pluralize(Singularize(t.Name)))
+}
+
+// schemaPrefix returns the PascalCase schema name starting the query names
+// of a schema-qualified table, keeping them apart from the names of tables
+// with the same name in DefaultSchema.
+func (g *Generator) schemaPrefix(t *Table) string {
+	if g.qualified(t) {
+		return SnakeToPascal(t.Schema)
+	}
+	return ""
+}
+
+func (g *Generator) warnf(format string, args ...interface{}) {
+	if g.Warnings != nil {
+		fmt.Fprintf(g.Warnings, "warning: "+format+"\n", args...)
+	}
+}
+
+// pascalColumns joins the PascalCase names of cols with "And", e.g.
+// "TenantIdAndLoginName".
+func pascalColumns(cols []*Column) string {
+	names := make([]string, len(cols))
+	for i, c := range cols {
+		names[i] = SnakeToPascal(c.Name)
+	}
+	return strings.Join(names, "And")
+}
+
+func (g *Generator) ident(name string) string {
+	if g.QuoteIdentifiers {
+		return g.Dialect.QuoteIdent(name)
+	}
+	return Ident(g.Dialect, name)
+}
+
+func (g *Generator) idents(cols []*Column) []string {
+	names := make([]string, len(cols))
+	for i, c := range cols {
+		names[i] = g.ident(c.Name)
+	}
+	return names
+}
+
+// GroupByTable splits queries into one group per table, keeping the order in
+// which the tables and the queries of each table appear.
+func GroupByTable(queries []Query) [][]Query {
+	var groups [][]Query
+	index := make(map[*Table]int)
+	for _, q := range queries {
+		i, ok := index[q.Table]
+		if !ok {
+			i = len(groups)
+			index[q.Table] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], q)
+	}
+	return groups
+}
+
+// WriteQueries writes the queries to w, one per line. With sqlc set every
+// query is preceded by its sqlc annotation and followed by a blank line.
+func WriteQueries(w io.Writer, queries []Query, sqlc bool) error {
+	for _, q := range queries {
+		str := ""
+		if sqlc {
+			str += q.Comment() + "\n"
+		}
+		str += q.SQL + "\n"
+		if sqlc {
+			str += "\n"
+		}
+		if _, err := io.WriteString(w, str); err != nil {
+			return err
+		}
+	}
+	return nil
+}
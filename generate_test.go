@@ -0,0 +1,698 @@
+package sqlgen
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testSchema() *Schema {
+	def := "nextval('users_id_seq'::regclass)"
+	return &Schema{
+		Name: "public",
+		Tables: []*Table{
+			{
+				Name: "users",
+				Columns: []*Column{
+					{Name: "id", OrdinalPosition: 1, DataType: "integer", Default: &def, AutoIncrement: true},
+					{Name: "name", OrdinalPosition: 2, DataType: "character varying"},
+					{Name: "email", OrdinalPosition: 3, DataType: "character varying", Nullable: true},
+				},
+				PrimaryKey: &PrimaryKey{Name: "users_pkey", Columns: []string{"id"}},
+			},
+			{
+				Name: "order_items",
+				Columns: []*Column{
+					{Name: "order_id", OrdinalPosition: 1, DataType: "integer"},
+					{Name: "item_id", OrdinalPosition: 2, DataType: "integer"},
+					{Name: "quantity", OrdinalPosition: 3, DataType: "integer"},
+				},
+				PrimaryKey: &PrimaryKey{Name: "order_items_pkey", Columns: []string{"order_id", "item_id"}},
+			},
+			{
+				Name: "logs",
+				Columns: []*Column{
+					{Name: "message", OrdinalPosition: 1, DataType: "text"},
+				},
+			},
+		},
+	}
+}
+
+// render formats queries the way the commands print them with --sqlc.
+func render(queries []Query) string {
+	var buf strings.Builder
+	if err := WriteQueries(&buf, queries, true); err != nil {
+		panic(err)
+	}
+	return buf.String()
+}
+
+var crudActions = []Action{ActionCreate, ActionRead, ActionUpdate, ActionDelete}
+
+func TestGeneratePostgres(t *testing.T) {
+	g := &Generator{Dialect: PostgresDialect{}, Actions: crudActions}
+	expected := `-- name: CreateUser :one
+INSERT INTO users (name, email) VALUES ($1, $2) RETURNING *;
+
+-- name: CreateOrderItem :one
+INSERT INTO order_items (order_id, item_id, quantity) VALUES ($1, $2, $3) RETURNING *;
+
+-- name: CreateLog :one
+INSERT INTO logs (message) VALUES ($1) RETURNING *;
+
+-- name: GetUserByPk :one
+SELECT id, name, email FROM users WHERE id = $1;
+
+-- name: GetOrderItemByPk :one
+SELECT order_id, item_id, quantity FROM order_items WHERE order_id = $1 AND item_id = $2;
+
+-- name: UpdateUser :one
+UPDATE users SET name = $1, email = $2 WHERE id = $3 RETURNING *;
+
+-- name: UpdateOrderItem :one
+UPDATE order_items SET quantity = $1 WHERE order_id = $2 AND item_id = $3 RETURNING *;
+
+-- name: DeleteUser :execrows
+DELETE FROM users WHERE id = $1;
+
+-- name: DeleteOrderItem :execrows
+DELETE FROM order_items WHERE order_id = $1 AND item_id = $2;
+
+`
+	if got := render(g.Generate(testSchema())); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestGenerateMySQL(t *testing.T) {
+	g := &Generator{Dialect: MySQLDialect{}, Actions: crudActions}
+	expected := `-- name: CreateUser :exec
+INSERT INTO users (name, email) VALUES (?, ?);
+
+-- name: CreateOrderItem :exec
+INSERT INTO order_items (order_id, item_id, quantity) VALUES (?, ?, ?);
+
+-- name: CreateLog :exec
+INSERT INTO logs (message) VALUES (?);
+
+-- name: GetUserByPk :one
+SELECT id, name, email FROM users WHERE id = ?;
+
+-- name: GetOrderItemByPk :one
+SELECT order_id, item_id, quantity FROM order_items WHERE order_id = ? AND item_id = ?;
+
+-- name: UpdateUser :exec
+UPDATE users SET name = ?, email = ? WHERE id = ?;
+
+-- name: UpdateOrderItem :exec
+UPDATE order_items SET quantity = ? WHERE order_id = ? AND item_id = ?;
+
+-- name: DeleteUser :execrows
+DELETE FROM users WHERE id = ?;
+
+-- name: DeleteOrderItem :execrows
+DELETE FROM order_items WHERE order_id = ? AND item_id = ?;
+
+`
+	if got := render(g.Generate(testSchema())); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestGenerateQuotesIdentifiers(t *testing.T) {
+	schema := &Schema{Tables: []*Table{{
+		Name: "UserProfiles",
+		Columns: []*Column{
+			{Name: "ID", OrdinalPosition: 1},
+			{Name: "display name", OrdinalPosition: 2},
+		},
+		PrimaryKey: &PrimaryKey{Columns: []string{"ID"}},
+	}}}
+
+	g := &Generator{Dialect: PostgresDialect{}}
+	q, ok := g.SelectByPk(schema.Tables[0])
+	if !ok {
+		t.Fatal("SelectByPk reported no query")
+	}
+	expected := `SELECT "ID", "display name" FROM "UserProfiles" WHERE "ID" = $1;`
+	if q.SQL != expected {
+		t.Errorf("expected %q, got %q", expected, q.SQL)
+	}
+}
+
+func TestGenerateQuotesReservedWords(t *testing.T) {
+	table := &Table{
+		Schema: "sales",
+		Name:   "order",
+		Columns: []*Column{
+			{Name: "id", OrdinalPosition: 1, AutoIncrement: true},
+			{Name: "user", OrdinalPosition: 2},
+			{Name: "group", OrdinalPosition: 3},
+		},
+		PrimaryKey: &PrimaryKey{Columns: []string{"id"}},
+	}
+
+	tests := []struct {
+		name      string
+		generator Generator
+		expected  string
+	}{
+		{
+			name:      "postgres",
+			generator: Generator{Dialect: PostgresDialect{}},
+			expected:  `INSERT INTO "order" ("user", "group") VALUES ($1, $2) RETURNING *;`,
+		},
+		{
+			name:      "mysql",
+			generator: Generator{Dialect: MySQLDialect{}},
+			expected:  "INSERT INTO `order` (user, `group`) VALUES (?, ?);",
+		},
+		{
+			name:      "quote identifiers",
+			generator: Generator{Dialect: PostgresDialect{}, DefaultSchema: "public", QuoteIdentifiers: true},
+			expected:  `INSERT INTO "sales"."order" ("user", "group") VALUES ($1, $2) RETURNING *;`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, ok := tt.generator.Insert(table)
+			if !ok {
+				t.Fatal("Insert reported no query")
+			}
+			if q.SQL != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, q.SQL)
+			}
+		})
+	}
+}
+
+func TestWriteQueriesWithoutSqlc(t *testing.T) {
+	queries := []Query{
+		{Name: "CreateUser", Cmd: ":one", SQL: "INSERT INTO users (name) VALUES ($1) RETURNING *;"},
+		{Name: "GetUserByPk", Cmd: ":one", SQL: "SELECT id, name FROM users WHERE id = $1;"},
+	}
+
+	var buf strings.Builder
+	if err := WriteQueries(&buf, queries, false); err != nil {
+		t.Fatalf("WriteQueries failed: %v", err)
+	}
+	expected := "INSERT INTO users (name) VALUES ($1) RETURNING *;\nSELECT id, name FROM users WHERE id = $1;\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestGroupByTable(t *testing.T) {
+	g := &Generator{Dialect: PostgresDialect{}, Actions: []Action{ActionCreate, ActionRead}}
+	var names [][]string
+	for _, group := range GroupByTable(g.Generate(testSchema())) {
+		var groupNames []string
+		for _, q := range group {
+			groupNames = append(groupNames, q.Table.Name+"."+q.Name)
+		}
+		names = append(names, groupNames)
+	}
+
+	expected := [][]string{
+		{"users.CreateUser", "users.GetUserByPk"},
+		{"order_items.CreateOrderItem", "order_items.GetOrderItemByPk"},
+		{"logs.CreateLog"},
+	}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+}
+
+func TestUpdateByPkSkipsTables(t *testing.T) {
+	g := &Generator{Dialect: PostgresDialect{}}
+	testCases := []struct {
+		name  string
+		table *Table
+	}{
+		{
+			name:  "without primary key",
+			table: &Table{Name: "logs", Columns: []*Column{{Name: "message"}}},
+		},
+		{
+			name: "only primary key columns",
+			table: &Table{
+				Name:       "post_tags",
+				Columns:    []*Column{{Name: "post_id"}, {Name: "tag_id"}},
+				PrimaryKey: &PrimaryKey{Columns: []string{"post_id", "tag_id"}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if q, ok := g.UpdateByPk(tc.table); ok {
+				t.Errorf("expected no query, got %q", q.SQL)
+			}
+		})
+	}
+}
+
+func TestDeleteByPkWarnsWithoutPrimaryKey(t *testing.T) {
+	var warnings strings.Builder
+	g := &Generator{Dialect: MySQLDialect{}, Warnings: &warnings}
+	table := &Table{Name: "logs", Columns: []*Column{{Name: "message"}}}
+
+	if queries := g.Queries(ActionDelete, table); len(queries) != 0 {
+		t.Errorf("expected no query, got %v", queries)
+	}
+	expected := "warning: skipping DELETE for table logs: no primary key\n"
+	if warnings.String() != expected {
+		t.Errorf("expected warning %q, got %q", expected, warnings.String())
+	}
+}
+
+func TestGenerateActions(t *testing.T) {
+	g := &Generator{Dialect: PostgresDialect{}, Actions: []Action{ActionDelete, ActionRead}}
+	var names []string
+	for _, q := range g.Generate(testSchema()) {
+		names = append(names, q.Name)
+	}
+	// queries follow the order of Actions, not of Generator.Actions
+	expected := "GetUserByPk GetOrderItemByPk DeleteUser DeleteOrderItem"
+	if strings.Join(names, " ") != expected {
+		t.Errorf("expected %q, got %q", expected, strings.Join(names, " "))
+	}
+}
+
+func TestGenerateQueryNames(t *testing.T) {
+	g := &Generator{Dialect: PostgresDialect{}, Actions: []Action{ActionRead}, QueryNames: map[string]string{"GetUserByPk": "GetUser"}}
+	var names []string
+	for _, q := range g.Generate(testSchema()) {
+		names = append(names, q.Name)
+	}
+	expected := "GetUser GetOrderItemByPk"
+	if strings.Join(names, " ") != expected {
+		t.Errorf("expected %q, got %q", expected, strings.Join(names, " "))
+	}
+}
+
+func TestGenerateTableOptions(t *testing.T) {
+	g := &Generator{
+		Dialect: PostgresDialect{},
+		Actions: crudActions,
+		Tables: map[string]TableOptions{
+			"users":       {Name: "Member", ReadOnlyColumns: []string{"name"}, HiddenColumns: []string{"email"}},
+			"order_items": {Actions: []Action{ActionList}},
+		},
+	}
+	expected := `-- name: CreateMember :one
+INSERT INTO users (email) VALUES ($1) RETURNING id, name;
+
+-- name: CreateLog :one
+INSERT INTO logs (message) VALUES ($1) RETURNING *;
+
+-- name: GetMemberByPk :one
+SELECT id, name FROM users WHERE id = $1;
+
+-- name: UpdateMember :one
+UPDATE users SET email = $1 WHERE id = $2 RETURNING id, name;
+
+-- name: DeleteMember :execrows
+DELETE FROM users WHERE id = $1;
+
+-- name: ListOrderItems :many
+SELECT order_id, item_id, quantity FROM order_items ORDER BY order_id, item_id LIMIT $1 OFFSET $2;
+
+-- name: ListOrderItemsAfter :many
+SELECT order_id, item_id, quantity FROM order_items WHERE (order_id, item_id) > ($1, $2) ORDER BY order_id, item_id LIMIT $3;
+
+`
+	if got := render(g.Generate(testSchema())); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestGenerateSchemas(t *testing.T) {
+	users := func(schema string) *Table {
+		return &Table{
+			Schema:     schema,
+			Name:       "users",
+			Columns:    []*Column{{Name: "id", OrdinalPosition: 1, DataType: "integer"}, {Name: "name", OrdinalPosition: 2, DataType: "text"}},
+			PrimaryKey: &PrimaryKey{Name: "users_pkey", Columns: []string{"id"}},
+		}
+	}
+	schema := &Schema{Tables: []*Table{users("public"), users("Audit"), users("archive")}}
+	g := &Generator{
+		Dialect:       PostgresDialect{},
+		Actions:       []Action{ActionRead},
+		DefaultSchema: "public",
+		Tables:        map[string]TableOptions{"archive.users": {Name: "ArchivedUser"}, "users": {HiddenColumns: []string{"name"}}},
+	}
+	expected := `-- name: GetUserByPk :one
+SELECT id FROM users WHERE id = $1;
+
+-- name: GetAuditUserByPk :one
+SELECT id FROM "Audit".users WHERE id = $1;
+
+-- name: GetArchivedUserByPk :one
+SELECT id, name FROM archive.users WHERE id = $1;
+
+`
+	if got := render(g.Generate(schema)); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+}
+
+func TestGenerateTableKinds(t *testing.T) {
+	table := func(name string, kind TableKind) *Table {
+		return &Table{
+			Name:       name,
+			Kind:       kind,
+			Columns:    []*Column{{Name: "id", OrdinalPosition: 1, DataType: "integer"}},
+			PrimaryKey: &PrimaryKey{Name: name + "_pkey", Columns: []string{"id"}},
+		}
+	}
+	view := table("active_users", KindView)
+	view.PrimaryKey = nil
+	schema := &Schema{Tables: []*Table{
+		table("events", KindPartitionedTable),
+		view,
+		table("user_stats", KindMaterializedView),
+		table("remote_users", KindForeignTable),
+	}}
+
+	var warnings strings.Builder
+	g := &Generator{Dialect: PostgresDialect{}, Actions: []Action{ActionCreate, ActionRead, ActionDelete}, Warnings: &warnings}
+	var names []string
+	for _, q := range g.Generate(schema) {
+		names = append(names, q.Name)
+	}
+	expected := "CreateEvent GetEventByPk GetUserStatByPk GetRemoteUserByPk DeleteEvent"
+	if strings.Join(names, " ") != expected {
+		t.Errorf("expected %q, got %q", expected, strings.Join(names, " "))
+	}
+	if warnings.Len() > 0 {
+		t.Errorf("expected no warnings for read-only tables, got %q", warnings.String())
+	}
+}
+
+func TestGenerateGeneratedColumns(t *testing.T) {
+	now := "now()"
+	table := &Table{
+		Name: "orders",
+		Columns: []*Column{
+			{Name: "id", OrdinalPosition: 1, DataType: "bigint", AutoIncrement: true, Identity: IdentityAlways},
+			{Name: "price", OrdinalPosition: 2, DataType: "integer"},
+			{Name: "quantity", OrdinalPosition: 3, DataType: "integer"},
+			{Name: "total", OrdinalPosition: 4, DataType: "integer", Nullable: true, Generated: true},
+			{Name: "created_at", OrdinalPosition: 5, DataType: "timestamp with time zone", Default: &now},
+		},
+		PrimaryKey: &PrimaryKey{Name: "orders_pkey", Columns: []string{"id"}},
+	}
+	schema := &Schema{Tables: []*Table{table}}
+
+	tests := []struct {
+		name      string
+		generator Generator
+		expected  []string
+	}{
+		{
+			name:      "default",
+			generator: Generator{Dialect: PostgresDialect{}, Actions: []Action{ActionCreate, ActionUpsert, ActionUpdate}},
+			expected: []string{
+				"INSERT INTO orders (price, quantity, created_at) VALUES ($1, $2, $3) RETURNING *;",
+				"INSERT INTO orders (id, price, quantity, created_at) OVERRIDING SYSTEM VALUE VALUES ($1, $2, $3, $4) ON CONFLICT (id) DO UPDATE SET price = EXCLUDED.price, quantity = EXCLUDED.quantity, created_at = EXCLUDED.created_at RETURNING *;",
+				"UPDATE orders SET price = $1, quantity = $2, created_at = $3 WHERE id = $4 RETURNING *;",
+			},
+		},
+		{
+			name:      "insert identity",
+			generator: Generator{Dialect: PostgresDialect{}, Actions: []Action{ActionCreate}, InsertIdentity: true},
+			expected: []string{
+				"INSERT INTO orders (id, price, quantity, created_at) OVERRIDING SYSTEM VALUE VALUES ($1, $2, $3, $4) RETURNING *;",
+			},
+		},
+		{
+			name:      "skip defaults",
+			generator: Generator{Dialect: PostgresDialect{}, Actions: []Action{ActionCreate}, SkipDefaults: true},
+			expected: []string{
+				"INSERT INTO orders (price, quantity) VALUES ($1, $2) RETURNING *;",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sqls []string
+			for _, q := range tt.generator.Generate(schema) {
+				sqls = append(sqls, q.SQL)
+			}
+			if !reflect.DeepEqual(sqls, tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, sqls)
+			}
+		})
+	}
+}
+
+func TestParseActions(t *testing.T) {
+	actions, err := ParseActions("create, list")
+	if err != nil {
+		t.Fatalf("ParseActions failed: %v", err)
+	}
+	if len(actions) != 2 || actions[0] != ActionCreate || actions[1] != ActionList {
+		t.Errorf("unexpected actions: %v", actions)
+	}
+
+	if _, err := ParseActions("create,merge"); err == nil {
+		t.Error("expected error for unknown action")
+	}
+}
+
+func TestList(t *testing.T) {
+	testCases := []struct {
+		name     string
+		dialect  Dialect
+		table    string
+		expected []string
+	}{
+		{
+			name:    "postgres single key",
+			dialect: PostgresDialect{},
+			table:   "users",
+			expected: []string{
+				"-- name: ListUsers :many",
+				"SELECT id, name, email FROM users ORDER BY id LIMIT $1 OFFSET $2;",
+				"-- name: ListUsersAfter :many",
+				"SELECT id, name, email FROM users WHERE id > $1 ORDER BY id LIMIT $2;",
+			},
+		},
+		{
+			name:    "postgres composite key",
+			dialect: PostgresDialect{},
+			table:   "order_items",
+			expected: []string{
+				"-- name: ListOrderItems :many",
+				"SELECT order_id, item_id, quantity FROM order_items ORDER BY order_id, item_id LIMIT $1 OFFSET $2;",
+				"-- name: ListOrderItemsAfter :many",
+				"SELECT order_id, item_id, quantity FROM order_items WHERE (order_id, item_id) > ($1, $2) ORDER BY order_id, item_id LIMIT $3;",
+			},
+		},
+		{
+			name:    "mysql composite key",
+			dialect: MySQLDialect{},
+			table:   "order_items",
+			expected: []string{
+				"-- name: ListOrderItems :many",
+				"SELECT order_id, item_id, quantity FROM order_items ORDER BY order_id, item_id LIMIT ? OFFSET ?;",
+				"-- name: ListOrderItemsAfter :many",
+				"SELECT order_id, item_id, quantity FROM order_items WHERE (order_id, item_id) > (?, ?) ORDER BY order_id, item_id LIMIT ?;",
+			},
+		},
+		{
+			name:    "without primary key",
+			dialect: PostgresDialect{},
+			table:   "logs",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := &Generator{Dialect: tc.dialect}
+			var got []string
+			for _, q := range g.List(testSchema().Table(tc.table)) {
+				got = append(got, q.Comment(), q.SQL)
+			}
+			if strings.Join(got, "\n") != strings.Join(tc.expected, "\n") {
+				t.Errorf("expected:\n%s\ngot:\n%s", strings.Join(tc.expected, "\n"), strings.Join(got, "\n"))
+			}
+		})
+	}
+}
+
+func TestLookups(t *testing.T) {
+	table := &Table{
+		Name: "users",
+		Columns: []*Column{
+			{Name: "id"},
+			{Name: "tenant_id"},
+			{Name: "email"},
+			{Name: "login_name"},
+		},
+		PrimaryKey: &PrimaryKey{Columns: []string{"id"}},
+		Indexes: []*Index{
+			{Name: "users_email_idx", Columns: []string{"email"}},
+			{Name: "users_email_key", Columns: []string{"email"}, Unique: true},
+			{Name: "users_email_key2", Columns: []string{"email"}, Unique: true},
+			{Name: "users_id_key", Columns: []string{"id"}, Unique: true},
+			{Name: "users_tenant_id_login_name_key", Columns: []string{"tenant_id", "login_name"}, Unique: true},
+		},
+	}
+
+	g := &Generator{Dialect: PostgresDialect{}}
+	var got []string
+	for _, q := range g.Queries(ActionLookup, table) {
+		got = append(got, q.Comment(), q.SQL)
+	}
+	expected := []string{
+		"-- name: GetUserByEmail :one",
+		"SELECT id, tenant_id, email, login_name FROM users WHERE email = $1;",
+		"-- name: GetUserByTenantIdAndLoginName :one",
+		"SELECT id, tenant_id, email, login_name FROM users WHERE tenant_id = $1 AND login_name = $2;",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestForeignKeyQueries(t *testing.T) {
+	table := &Table{
+		Name: "orders",
+		Columns: []*Column{
+			{Name: "id"},
+			{Name: "user_id"},
+			{Name: "shop_id"},
+			{Name: "shop_region"},
+		},
+		PrimaryKey: &PrimaryKey{Columns: []string{"id"}},
+		ForeignKeys: []*ForeignKey{
+			{Name: "orders_user_id_fkey", Columns: []string{"user_id"}, ReferencedTable: "users", ReferencedColumns: []string{"id"}},
+			{Name: "orders_user_id_fkey2", Columns: []string{"user_id"}, ReferencedTable: "accounts", ReferencedColumns: []string{"id"}},
+			{Name: "orders_shop_fkey", Columns: []string{"shop_id", "shop_region"}, ReferencedTable: "shops", ReferencedColumns: []string{"id", "region"}},
+		},
+	}
+
+	g := &Generator{Dialect: MySQLDialect{}, Actions: []Action{ActionListByFK, ActionCountByFK}}
+	var got []string
+	for _, q := range g.Generate(&Schema{Tables: []*Table{table}}) {
+		got = append(got, q.Comment(), q.SQL)
+	}
+	expected := []string{
+		"-- name: ListOrdersByUserId :many",
+		"SELECT id, user_id, shop_id, shop_region FROM orders WHERE user_id = ? ORDER BY id;",
+		"-- name: ListOrdersByShopIdAndShopRegion :many",
+		"SELECT id, user_id, shop_id, shop_region FROM orders WHERE shop_id = ? AND shop_region = ? ORDER BY id;",
+		"-- name: CountOrdersByUserId :one",
+		"SELECT COUNT(*) FROM orders WHERE user_id = ?;",
+		"-- name: CountOrdersByShopIdAndShopRegion :one",
+		"SELECT COUNT(*) FROM orders WHERE shop_id = ? AND shop_region = ?;",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestGenerateDefaultActions(t *testing.T) {
+	table := &Table{
+		Name:        "orders",
+		Columns:     []*Column{{Name: "user_id"}},
+		ForeignKeys: []*ForeignKey{{Name: "orders_user_id_fkey", Columns: []string{"user_id"}}},
+	}
+
+	g := &Generator{Dialect: PostgresDialect{}}
+	for _, q := range g.Generate(&Schema{Tables: []*Table{table}}) {
+		if q.Action == ActionCountByFK {
+			t.Errorf("%s must not be generated by default", q.Name)
+		}
+	}
+}
+
+func TestUpsert(t *testing.T) {
+	schema := testSchema()
+	schema.Tables = append(schema.Tables, &Table{
+		Name:       "post_tags",
+		Columns:    []*Column{{Name: "post_id", DataType: "integer"}, {Name: "tag_id", DataType: "integer"}},
+		PrimaryKey: &PrimaryKey{Name: "post_tags_pkey", Columns: []string{"post_id", "tag_id"}},
+	})
+	schema.Table("users").Indexes = []*Index{
+		{Name: "users_email_idx", Columns: []string{"email"}},
+		{Name: "users_email_key", Columns: []string{"email"}, Unique: true},
+	}
+
+	testCases := []struct {
+		name       string
+		dialect    Dialect
+		table      string
+		upsertKeys map[string]string
+		expected   string
+		warning    string
+	}{
+		{
+			name:     "postgres primary key",
+			dialect:  PostgresDialect{},
+			table:    "users",
+			expected: "INSERT INTO users (id, name, email) VALUES ($1, $2, $3) ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name, email = EXCLUDED.email RETURNING *;",
+		},
+		{
+			name:       "postgres unique key",
+			dialect:    PostgresDialect{},
+			table:      "users",
+			upsertKeys: map[string]string{"users": "users_email_key"},
+			expected:   "INSERT INTO users (name, email) VALUES ($1, $2) ON CONFLICT (email) DO UPDATE SET name = EXCLUDED.name RETURNING *;",
+		},
+		{
+			name:     "mysql composite primary key",
+			dialect:  MySQLDialect{},
+			table:    "order_items",
+			expected: "INSERT INTO order_items (order_id, item_id, quantity) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE quantity = VALUES(quantity);",
+		},
+		{
+			name:     "mysql row alias",
+			dialect:  MySQLDialect{RowAlias: true},
+			table:    "order_items",
+			expected: "INSERT INTO order_items (order_id, item_id, quantity) VALUES (?, ?, ?) AS new ON DUPLICATE KEY UPDATE quantity = new.quantity;",
+		},
+		{
+			name:     "postgres key columns only",
+			dialect:  PostgresDialect{},
+			table:    "post_tags",
+			expected: "INSERT INTO post_tags (post_id, tag_id) VALUES ($1, $2) ON CONFLICT (post_id, tag_id) DO UPDATE SET post_id = EXCLUDED.post_id RETURNING *;",
+		},
+		{
+			name:     "mysql key columns only",
+			dialect:  MySQLDialect{},
+			table:    "post_tags",
+			expected: "INSERT INTO post_tags (post_id, tag_id) VALUES (?, ?) ON DUPLICATE KEY UPDATE post_id = VALUES(post_id);",
+		},
+		{
+			name:    "without primary key",
+			dialect: PostgresDialect{},
+			table:   "logs",
+		},
+		{
+			name:       "non-unique key",
+			dialect:    PostgresDialect{},
+			table:      "users",
+			upsertKeys: map[string]string{"users": "users_email_idx"},
+			warning:    "warning: skipping upsert for table users: no unique key users_email_idx\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var warnings strings.Builder
+			g := &Generator{Dialect: tc.dialect, UpsertKeys: tc.upsertKeys, Warnings: &warnings}
+			q, ok := g.Upsert(schema.Table(tc.table))
+			if q.SQL != tc.expected || ok != (tc.expected != "") {
+				t.Errorf("expected %q, got %q (%v)", tc.expected, q.SQL, ok)
+			}
+			if warnings.String() != tc.warning {
+				t.Errorf("expected warning %q, got %q", tc.warning, warnings.String())
+			}
+		})
+	}
+}
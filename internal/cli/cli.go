@@ -0,0 +1,380 @@
+// Package cli implements the commands of the sqlgen, psqlgen and mysqlgen
+// binaries.
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/miyataka/sqlgen"
+	"github.com/miyataka/sqlgen/ddl"
+	"github.com/spf13/cobra"
+)
+
+// options holds the flags shared by all generating commands, completed by
+// the config file.
+type options struct {
+	// configPath is the config file; sqlgen.yaml is used if it exists.
+	configPath string
+	// driver names the database of the root command.
+	driver string
+	dsn    string
+	// schemaFiles are DDL files read instead of connecting to dsn.
+	schemaFiles []string
+	// migrationsDir is a migrations directory replayed instead of
+	// connecting to dsn.
+	migrationsDir string
+	// fromSnapshot is a schema snapshot read instead of connecting to dsn.
+	fromSnapshot string
+	// dumpSchema is the file the introspected schema is saved to.
+	dumpSchema string
+	// outDir is the directory of the per-table files written instead of
+	// stdout.
+	outDir          string
+	filenamePattern string
+	// goOut is the Go file the models of the tables are written to, in
+	// package goPackage or the one named after its directory.
+	goOut          string
+	goPackage      string
+	goNullPointers bool
+	// goRepo is the Go file of the functions running the queries, next to
+	// goOut.
+	goRepo string
+	// check compares the files in outDir and goOut with the generated
+	// ones instead of writing them.
+	check      bool
+	sqlc       bool
+	skipTables string
+	actions    string
+	upsertKeys map[string]string
+	// mysqlRowAlias selects the MySQL 8.0.19 row alias syntax for upserts.
+	mysqlRowAlias bool
+	// insertIdentity and skipDefaults choose the columns of INSERT as in
+	// sqlgen.Generator.
+	insertIdentity bool
+	skipDefaults   bool
+	// quoteIdentifiers quotes every identifier, not only the ones that
+	// require it.
+	quoteIdentifiers bool
+	// schemas are the schemas read instead of the default schema of the
+	// driver.
+	schemas []string
+	// include and exclude are the table patterns of the TableFilter.
+	include    []string
+	exclude    []string
+	tables     map[string]sqlgen.TableOptions
+	queryNames map[string]string
+}
+
+func (o *options) addFlags(cmd *cobra.Command, dsnExample string) {
+	cmd.Flags().StringVar(&o.configPath, "config", "", "config file (default sqlgen.yaml in the working directory if it exists)")
+	cmd.Flags().StringVarP(&o.dsn, "dsn", "d", "", "DSN e.g. "+dsnExample)
+	cmd.Flags().StringSliceVar(&o.schemaFiles, "schema-file", nil, "DDL file to read the schema from instead of connecting to a database; repeat to apply several files in order")
+	cmd.Flags().BoolVar(&o.sqlc, "sqlc", false, "generate comment for sqlc")
+	cmd.Flags().StringVar(&o.skipTables, "skip-tables", "", "comma-separated list of tables to skip; same as --exclude")
+	cmd.Flags().StringSliceVar(&o.include, "include", nil, "only generate for tables matching these globs or /regular expressions/, optionally schema-qualified e.g. public.users,/events_\\d+/")
+	cmd.Flags().StringSliceVar(&o.exclude, "exclude", nil, "skip tables matching these globs or /regular expressions/, optionally schema-qualified e.g. audit_*,archive.*")
+	cmd.Flags().StringVar(&o.actions, "actions", "", "comma-separated list of actions to generate (default all but count-by-fk) e.g. create,read,update,delete,list")
+	cmd.Flags().StringToStringVar(&o.upsertKeys, "upsert-key", nil, "unique constraint or index an upsert conflicts on instead of the primary key e.g. users=users_email_key")
+	cmd.Flags().BoolVar(&o.mysqlRowAlias, "mysql-row-alias", false, "use the row alias syntax of MySQL 8.0.19+ in upserts instead of VALUES()")
+	cmd.Flags().BoolVar(&o.insertIdentity, "insert-identity", false, "also insert serial, identity and AUTO_INCREMENT columns, e.g. to copy rows with their keys")
+	cmd.Flags().BoolVar(&o.skipDefaults, "skip-defaults", false, "leave columns with a default value out of INSERT so that the database fills them")
+	cmd.Flags().BoolVar(&o.quoteIdentifiers, "quote-identifiers", false, "quote every table and column name instead of only reserved words and names with upper case letters or special characters")
+	cmd.Flags().StringSliceVar(&o.schemas, "schema", nil, "schema (PostgreSQL) or database (MySQL) to read; repeat to read several (default public, or the database of the DSN)")
+	cmd.Flags().StringVar(&o.migrationsDir, "migrations-dir", "", "golang-migrate, goose or dbmate migrations directory to replay instead of connecting to a database")
+	cmd.Flags().StringVar(&o.fromSnapshot, "from-snapshot", "", "schema snapshot written by --dump-schema to read instead of connecting to a database")
+	cmd.Flags().StringVar(&o.dumpSchema, "dump-schema", "", "file to save the schema to as a JSON snapshot")
+	cmd.Flags().StringVar(&o.outDir, "out-dir", "", "directory to write one file per table to instead of stdout")
+	cmd.Flags().StringVar(&o.filenamePattern, "filename-pattern", defaultFilenamePattern, "file name of each table under --out-dir; {schema} and {table} are replaced")
+	cmd.Flags().StringVar(&o.goOut, "go-out", "", "Go file to write one struct per table to")
+	cmd.Flags().StringVar(&o.goPackage, "go-package", "", "package of the --go-out file (default the name of its directory)")
+	cmd.Flags().StringVar(&o.goRepo, "go-repo", "", "Go file to write one database/sql function per query to, in the directory of --go-out")
+	cmd.Flags().BoolVar(&o.goNullPointers, "go-null-pointers", false, "type nullable columns as pointers instead of sql.Null types in --go-out")
+	cmd.Flags().BoolVar(&o.check, "check", false, "print a diff and fail if the files in --out-dir or --go-out differ from the generated ones instead of writing them")
+	cmd.MarkFlagsMutuallyExclusive("dsn", "schema-file", "migrations-dir", "from-snapshot")
+}
+
+// NewRootCommand returns the sqlgen command. It detects the database from
+// the DSN or snapshot, or takes it from --driver or the config file when
+// reading schema files or migrations, and has one subcommand per database.
+func NewRootCommand() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:           "sqlgen",
+		Short:         "sqlgen is a sql generator",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.prepare(cmd); err != nil {
+				return err
+			}
+			d, err := rootDriver(o)
+			if err != nil {
+				return err
+			}
+			return run(cmd.Context(), cmd.OutOrStdout(), cmd.ErrOrStderr(), d, o)
+		},
+	}
+	o.addFlags(cmd, Postgres.DSNExample)
+	cmd.Flags().StringVar(&o.driver, "driver", "", "database of the schema files or migrations: "+strings.Join(driverNames(), " or "))
+
+	for _, d := range Drivers {
+		sub := NewDriverCommand(d.Name, d)
+		sub.Short = fmt.Sprintf("generate sql for %s", d.Name)
+		cmd.AddCommand(sub)
+	}
+	return cmd
+}
+
+// NewDriverCommand returns a command generating queries for a single database.
+func NewDriverCommand(use string, d *Driver) *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:           use,
+		Short:         use + " is a sql generator",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := o.prepare(cmd); err != nil {
+				return err
+			}
+			if o.driver != "" && o.driver != d.Name {
+				return fmt.Errorf("the config file is for %s, not %s", o.driver, d.Name)
+			}
+			return run(cmd.Context(), cmd.OutOrStdout(), cmd.ErrOrStderr(), d, o)
+		},
+	}
+	o.addFlags(cmd, d.DSNExample)
+	return cmd
+}
+
+// rootDriver returns the driver of the root command: the one named by
+// --driver or the config file, or else the one detected from the DSN or
+// snapshot.
+func rootDriver(o *options) (*Driver, error) {
+	switch {
+	case o.driver != "":
+		return LookupDriver(o.driver)
+	case o.fromSnapshot != "":
+		snapshot, err := o.snapshot()
+		if err != nil {
+			return nil, err
+		}
+		return LookupDriver(snapshot.Dialect)
+	case o.dsn == "":
+		return nil, fmt.Errorf("--driver is required with --schema-file and --migrations-dir")
+	}
+	return DetectDriver(o.dsn)
+}
+
+// run reads the schema from the database of o.dsn, from o.schemaFiles or from
+// o.migrationsDir and writes the generated queries to stdout and warnings to
+// stderr.
+func run(ctx context.Context, stdout, stderr io.Writer, d *Driver, o *options) error {
+	var opts sqlgen.IntrospectOptions
+	var introspector sqlgen.Introspector
+	switch {
+	case len(o.schemaFiles) > 0:
+		introspector = &ddl.FileIntrospector{Dialect: d.Dialect, Paths: o.schemaFiles}
+	case o.migrationsDir != "":
+		introspector = &ddl.MigrationsIntrospector{Dialect: d.Dialect, Dir: o.migrationsDir}
+	case o.fromSnapshot != "":
+		snapshot, err := o.snapshot()
+		if err != nil {
+			return err
+		}
+		if snapshot.Dialect != d.Dialect.Name() {
+			return fmt.Errorf("%s is a snapshot of a %s schema, not %s", o.fromSnapshot, snapshot.Dialect, d.Dialect.Name())
+		}
+		introspector = sqlgen.StaticIntrospector{Schema: snapshot.Schema}
+	default:
+		dsn := d.normalizeDSN(o.dsn)
+		schema, err := d.DefaultSchema(dsn)
+		if err != nil {
+			return err
+		}
+		db, err := sql.Open(d.SQLDriver, dsn)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		opts.Schema = schema
+		introspector = d.NewIntrospector(db)
+	}
+
+	g, err := o.generator(d, stderr)
+	if err != nil {
+		return err
+	}
+	return o.generate(ctx, stdout, g, introspector, opts)
+}
+
+// snapshot reads the snapshot of --from-snapshot.
+func (o *options) snapshot() (*sqlgen.Snapshot, error) {
+	f, err := os.Open(o.fromSnapshot)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	snapshot, err := sqlgen.ReadSnapshot(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", o.fromSnapshot, err)
+	}
+	return snapshot, nil
+}
+
+// generator returns the Generator configured by the flags. With
+// --from-snapshot the default schema is the one of the snapshot, or the
+// schema read into it when older snapshots do not record it and the driver
+// does not know it offline.
+func (o *options) generator(d *Driver, warnings io.Writer) (*sqlgen.Generator, error) {
+	dsn := o.dsn
+	if dsn != "" {
+		dsn = d.normalizeDSN(dsn)
+	}
+	defaultSchema, err := d.DefaultSchema(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if o.fromSnapshot != "" {
+		snapshot, err := o.snapshot()
+		if err != nil {
+			return nil, err
+		}
+		if snapshot.DefaultSchema != "" {
+			defaultSchema = snapshot.DefaultSchema
+		} else if defaultSchema == "" {
+			defaultSchema = snapshot.Schema.Name
+		}
+	}
+	g := &sqlgen.Generator{
+		Dialect:          d.Dialect,
+		DefaultSchema:    defaultSchema,
+		UpsertKeys:       o.upsertKeys,
+		InsertIdentity:   o.insertIdentity,
+		SkipDefaults:     o.skipDefaults,
+		QuoteIdentifiers: o.quoteIdentifiers,
+		NullPointers:     o.goNullPointers,
+		Tables:           o.tables,
+		QueryNames:       o.queryNames,
+		Warnings:         warnings,
+	}
+	if _, ok := g.Dialect.(sqlgen.MySQLDialect); ok && o.mysqlRowAlias {
+		g.Dialect = sqlgen.MySQLDialect{RowAlias: true}
+	}
+	if o.actions != "" {
+		actions, err := sqlgen.ParseActions(o.actions)
+		if err != nil {
+			return nil, err
+		}
+		g.Actions = actions
+	}
+	return g, nil
+}
+
+// generate writes the statements for the introspected schema to w, or to one
+// file per table when --out-dir is set. With --check it writes the diff to
+// the existing files to w instead. It saves the schema first when
+// --dump-schema is set.
+func (o *options) generate(ctx context.Context, w io.Writer, g *sqlgen.Generator, introspector sqlgen.Introspector, opts sqlgen.IntrospectOptions) error {
+	if o.check && o.outDir == "" && o.goOut == "" {
+		return fmt.Errorf("--check requires --out-dir or --go-out")
+	}
+	if o.goRepo != "" && (o.goOut == "" || filepath.Dir(o.goRepo) != filepath.Dir(o.goOut)) {
+		return fmt.Errorf("--go-repo requires --go-out in the same directory")
+	}
+	filter, err := sqlgen.NewTableFilter(o.include, append(o.exclude, parseSkipTables(o.skipTables)...))
+	if err != nil {
+		return err
+	}
+	schema, err := o.introspect(ctx, introspector, opts)
+	if err != nil {
+		return err
+	}
+	schema = filter.Filter(schema)
+	if o.dumpSchema != "" {
+		if err := dumpSchema(o.dumpSchema, g, schema); err != nil {
+			return err
+		}
+	}
+	queries := g.Generate(schema)
+	var files []outputFile
+	if o.outDir != "" {
+		if files, err = o.files(queries); err != nil {
+			return err
+		}
+	} else if !o.check {
+		if err := sqlgen.WriteQueries(w, queries, o.sqlc); err != nil {
+			return err
+		}
+	}
+	if o.goOut != "" {
+		goFiles, err := o.goFiles(g, schema, queries)
+		if err != nil {
+			return err
+		}
+		files = append(files, goFiles...)
+	}
+	if o.check {
+		return checkFiles(w, files)
+	}
+	return writeFiles(files)
+}
+
+// introspect reads the schema of opts, or each of --schema into one schema
+// model. Tables without schema, e.g. from MySQL DDL, are read by every
+// schema and only added once.
+func (o *options) introspect(ctx context.Context, introspector sqlgen.Introspector, opts sqlgen.IntrospectOptions) (*sqlgen.Schema, error) {
+	if len(o.schemas) == 0 {
+		return introspector.Introspect(ctx, opts)
+	}
+	merged := &sqlgen.Schema{}
+	for i, name := range o.schemas {
+		opts.Schema = name
+		schema, err := introspector.Introspect(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range schema.Tables {
+			if t.Schema == "" && i > 0 {
+				continue
+			}
+			merged.Tables = append(merged.Tables, t)
+		}
+	}
+	if len(o.schemas) == 1 {
+		merged.Name = o.schemas[0]
+	}
+	return merged, nil
+}
+
+// dumpSchema saves schema as a snapshot at path, with the dialect and
+// default schema of g.
+func dumpSchema(path string, g *sqlgen.Generator, schema *sqlgen.Schema) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := sqlgen.WriteSnapshot(f, g.Dialect, g.DefaultSchema, schema); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// parseSkipTables splits a comma-separated list of table names.
+func parseSkipTables(s string) []string {
+	if s == "" {
+		return nil
+	}
+	tables := strings.Split(s, ",")
+	for i := range tables {
+		tables[i] = strings.TrimSpace(tables[i])
+	}
+	return tables
+}
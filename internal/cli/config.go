@@ -0,0 +1,252 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/miyataka/sqlgen"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfigFiles are looked up in the working directory when --config is
+// not given.
+var defaultConfigFiles = []string{"sqlgen.yaml", "sqlgen.yml"}
+
+// config is the content of a config file. Its keys mirror the flags, which
+// take precedence over it.
+type config struct {
+	Driver string `yaml:"driver"`
+	// DSN may refer to environment variables as $VAR or ${VAR}.
+	DSN           string   `yaml:"dsn"`
+	SchemaFiles   []string `yaml:"schema_files"`
+	MigrationsDir string   `yaml:"migrations_dir"`
+	FromSnapshot  string   `yaml:"from_snapshot"`
+	// Schemas are the schemas (PostgreSQL) or databases (MySQL) to read
+	// instead of the default one of the driver.
+	Schemas []string `yaml:"schemas"`
+	// Include and Exclude are table patterns as in sqlgen.TableFilter.
+	Include          []string          `yaml:"include"`
+	Exclude          []string          `yaml:"exclude"`
+	Actions          []string          `yaml:"actions"`
+	Sqlc             bool              `yaml:"sqlc"`
+	UpsertKeys       map[string]string `yaml:"upsert_keys"`
+	MySQLRowAlias    bool              `yaml:"mysql_row_alias"`
+	InsertIdentity   bool              `yaml:"insert_identity"`
+	SkipDefaults     bool              `yaml:"skip_defaults"`
+	QuoteIdentifiers bool              `yaml:"quote_identifiers"`
+	// Tables overrides the generation of single tables.
+	Tables map[string]tableConfig `yaml:"tables"`
+	// QueryNames renames queries, keyed by their generated name.
+	QueryNames      map[string]string `yaml:"query_names"`
+	DumpSchema      string            `yaml:"dump_schema"`
+	GoOut           string            `yaml:"go_out"`
+	GoPackage       string            `yaml:"go_package"`
+	GoNullPointers  bool              `yaml:"go_null_pointers"`
+	GoRepo          string            `yaml:"go_repo"`
+	OutDir          string            `yaml:"out_dir"`
+	FilenamePattern string            `yaml:"filename_pattern"`
+}
+
+// tableConfig overrides the generation of one table.
+type tableConfig struct {
+	Actions []string `yaml:"actions"`
+	// Name replaces the name derived from the table name in query names.
+	Name string `yaml:"name"`
+	// ReadOnlyColumns are never inserted or updated.
+	ReadOnlyColumns []string `yaml:"readonly_columns"`
+	// HiddenColumns are never selected.
+	HiddenColumns []string `yaml:"hidden_columns"`
+}
+
+// options returns the generator options of the table.
+func (c tableConfig) options() (sqlgen.TableOptions, error) {
+	opts := sqlgen.TableOptions{Name: c.Name, ReadOnlyColumns: c.ReadOnlyColumns, HiddenColumns: c.HiddenColumns}
+	if len(c.Actions) > 0 {
+		actions, err := sqlgen.ParseActions(strings.Join(c.Actions, ","))
+		if err != nil {
+			return sqlgen.TableOptions{}, err
+		}
+		opts.Actions = actions
+	}
+	return opts, nil
+}
+
+// readConfig reads the config file at path. Relative paths in it are
+// relative to the directory of the file.
+func readConfig(path string) (*config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	c := &config{}
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if c.DSN, err = expandEnv(c.DSN); err != nil {
+		return nil, fmt.Errorf("%s: dsn: %w", path, err)
+	}
+
+	dir := filepath.Dir(path)
+	for i := range c.SchemaFiles {
+		c.SchemaFiles[i] = resolvePath(dir, c.SchemaFiles[i])
+	}
+	for _, p := range []*string{&c.MigrationsDir, &c.FromSnapshot, &c.DumpSchema, &c.OutDir, &c.GoOut, &c.GoRepo} {
+		*p = resolvePath(dir, *p)
+	}
+	return c, nil
+}
+
+// expandEnv replaces $VAR and ${VAR} in s with the values of the environment
+// variables, and $$ with $. It fails when a variable is not set.
+func expandEnv(s string) (string, error) {
+	var missing []string
+	s = os.Expand(s, func(name string) string {
+		if name == "$" {
+			return "$"
+		}
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			missing = append(missing, name)
+		}
+		return value
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("environment variable %s is not set", strings.Join(missing, ", "))
+	}
+	return s, nil
+}
+
+// resolvePath returns path relative to dir unless it is empty or absolute.
+func resolvePath(dir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// findConfig returns the config file in the working directory, or "" if there
+// is none.
+func findConfig() string {
+	for _, name := range defaultConfigFiles {
+		if _, err := os.Stat(name); err == nil {
+			return name
+		}
+	}
+	return ""
+}
+
+// prepare reads the config file of --config, or the one found in the working
+// directory, into the options whose flags were not given, and checks that
+// there is a schema to read.
+func (o *options) prepare(cmd *cobra.Command) error {
+	path := o.configPath
+	if path == "" {
+		path = findConfig()
+	}
+	if path != "" {
+		c, err := readConfig(path)
+		if err != nil {
+			return err
+		}
+		if err := o.applyConfig(cmd, c); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	if o.dsn == "" && len(o.schemaFiles) == 0 && o.migrationsDir == "" && o.fromSnapshot == "" {
+		return fmt.Errorf("one of --dsn, --schema-file, --migrations-dir and --from-snapshot is required")
+	}
+	return nil
+}
+
+// applyConfig copies the settings of c into the options whose flags were not
+// given. The schema source of c is ignored when one is given by flag.
+func (o *options) applyConfig(cmd *cobra.Command, c *config) error {
+	changed := cmd.Flags().Changed
+	if !changed("dsn") && !changed("schema-file") && !changed("migrations-dir") && !changed("from-snapshot") {
+		sources := 0
+		for _, set := range []bool{c.DSN != "", len(c.SchemaFiles) > 0, c.MigrationsDir != "", c.FromSnapshot != ""} {
+			if set {
+				sources++
+			}
+		}
+		if sources > 1 {
+			return fmt.Errorf("only one of dsn, schema_files, migrations_dir and from_snapshot can be set")
+		}
+		o.dsn, o.schemaFiles, o.migrationsDir, o.fromSnapshot = c.DSN, c.SchemaFiles, c.MigrationsDir, c.FromSnapshot
+	}
+	if c.Schemas != nil && !changed("schema") {
+		o.schemas = c.Schemas
+	}
+	if c.Driver != "" && !changed("driver") {
+		o.driver = c.Driver
+	}
+	if c.Include != nil && !changed("include") {
+		o.include = c.Include
+	}
+	if c.Exclude != nil && !changed("exclude") && !changed("skip-tables") {
+		o.exclude = c.Exclude
+	}
+	if len(c.Actions) > 0 && !changed("actions") {
+		o.actions = strings.Join(c.Actions, ",")
+	}
+	if c.UpsertKeys != nil && !changed("upsert-key") {
+		o.upsertKeys = c.UpsertKeys
+	}
+	if !changed("sqlc") {
+		o.sqlc = c.Sqlc
+	}
+	if !changed("mysql-row-alias") {
+		o.mysqlRowAlias = c.MySQLRowAlias
+	}
+	if !changed("insert-identity") {
+		o.insertIdentity = c.InsertIdentity
+	}
+	if !changed("skip-defaults") {
+		o.skipDefaults = c.SkipDefaults
+	}
+	if !changed("quote-identifiers") {
+		o.quoteIdentifiers = c.QuoteIdentifiers
+	}
+	if c.DumpSchema != "" && !changed("dump-schema") {
+		o.dumpSchema = c.DumpSchema
+	}
+	if c.OutDir != "" && !changed("out-dir") {
+		o.outDir = c.OutDir
+	}
+	if c.FilenamePattern != "" && !changed("filename-pattern") {
+		o.filenamePattern = c.FilenamePattern
+	}
+	if c.GoOut != "" && !changed("go-out") {
+		o.goOut = c.GoOut
+	}
+	if c.GoRepo != "" && !changed("go-repo") {
+		o.goRepo = c.GoRepo
+	}
+	if c.GoPackage != "" && !changed("go-package") {
+		o.goPackage = c.GoPackage
+	}
+	if !changed("go-null-pointers") {
+		o.goNullPointers = c.GoNullPointers
+	}
+	o.queryNames = c.QueryNames
+	if len(c.Tables) > 0 {
+		o.tables = make(map[string]sqlgen.TableOptions, len(c.Tables))
+		for name, t := range c.Tables {
+			opts, err := t.options()
+			if err != nil {
+				return fmt.Errorf("tables.%s: %w", name, err)
+			}
+			o.tables[name] = opts
+		}
+	}
+	return nil
+}
@@ -1,4 +1,4 @@
-package main
+package cli
 
 import (
 	"context"
@@ -7,17 +7,19 @@ import (
 	"testing"
 
 	_ "github.com/go-sql-driver/mysql"
-	"github.com/testcontainers/testcontainers-go/modules/mysql"
+	"github.com/miyataka/sqlgen"
+	"github.com/miyataka/sqlgen/mysql"
+	tcmysql "github.com/testcontainers/testcontainers-go/modules/mysql"
 )
 
 func TestMySQLIntegration(t *testing.T) {
 	ctx := context.Background()
 
-	mysqlContainer, err := mysql.Run(ctx,
+	mysqlContainer, err := tcmysql.Run(ctx,
 		"mysql:8",
-		mysql.WithDatabase("testdb"),
-		mysql.WithUsername("root"),
-		mysql.WithPassword("password"),
+		tcmysql.WithDatabase("testdb"),
+		tcmysql.WithUsername("root"),
+		tcmysql.WithPassword("password"),
 	)
 	if err != nil {
 		t.Fatalf("failed to start container: %s", err)
@@ -64,8 +66,8 @@ func TestMySQLIntegration(t *testing.T) {
 	`
 
 	// MySQL requires executing statements one at a time
-	statements := strings.Split(createTableSQL, ";")
-	for _, stmt := range statements {
+	stmts := strings.Split(createTableSQL, ";")
+	for _, stmt := range stmts {
 		stmt = strings.TrimSpace(stmt)
 		if stmt != "" {
 			_, err = db.Exec(stmt)
@@ -75,14 +77,15 @@ func TestMySQLIntegration(t *testing.T) {
 		}
 	}
 
-	// Test getInsertsStmts
+	// Test INSERT generation
 	t.Run("TestGetInsertStatements", func(t *testing.T) {
 		database := "testdb" // MySQL database name from container
 
-		insertStmts, err := getInsertsStmts(ctx, db, database, []string{})
+		s, err := mysql.NewIntrospector(db).Introspect(ctx, sqlgen.IntrospectOptions{Schema: database})
 		if err != nil {
-			t.Fatalf("failed to get insert statements: %s", err)
+			t.Fatalf("failed to get schema: %s", err)
 		}
+		insertStmts := statements(MySQL, s, sqlgen.ActionCreate)
 
 		if len(insertStmts) != 3 {
 			t.Errorf("expected 3 insert statements, got %d", len(insertStmts))
@@ -116,14 +119,15 @@ func TestMySQLIntegration(t *testing.T) {
 		}
 	})
 
-	// Test getSelectByPkStmts
+	// Test SELECT generation
 	t.Run("TestGetSelectStatements", func(t *testing.T) {
 		database := "testdb"
 
-		selectStmts, err := getSelectsStmts(ctx, db, database, []string{})
+		s, err := mysql.NewIntrospector(db).Introspect(ctx, sqlgen.IntrospectOptions{Schema: database})
 		if err != nil {
-			t.Fatalf("failed to get select statements: %s", err)
+			t.Fatalf("failed to get schema: %s", err)
 		}
+		selectStmts := statements(MySQL, s, sqlgen.ActionRead)
 
 		if len(selectStmts) != 3 {
 			t.Errorf("expected 3 select statements, got %d", len(selectStmts))
@@ -157,16 +161,88 @@ func TestMySQLIntegration(t *testing.T) {
 		}
 	})
 
+	// Test UPDATE generation
+	t.Run("TestGetUpdateStatements", func(t *testing.T) {
+		database := "testdb"
+
+		s, err := mysql.NewIntrospector(db).Introspect(ctx, sqlgen.IntrospectOptions{Schema: database})
+		if err != nil {
+			t.Fatalf("failed to get schema: %s", err)
+		}
+		updateStmts := statements(MySQL, s, sqlgen.ActionUpdate)
+
+		if len(updateStmts) != 3 {
+			t.Errorf("expected 3 update statements, got %d", len(updateStmts))
+		}
+		for _, stmt := range updateStmts {
+			if strings.Contains(strings.ToLower(stmt), "set id =") {
+				t.Errorf("primary key must not be updated: %s", stmt)
+			}
+		}
+	})
+
+	// Test unique lookup generation
+	t.Run("TestGetLookupStatements", func(t *testing.T) {
+		database := "testdb"
+
+		s, err := mysql.NewIntrospector(db).Introspect(ctx, sqlgen.IntrospectOptions{Schema: database})
+		if err != nil {
+			t.Fatalf("failed to get schema: %s", err)
+		}
+		lookupStmts := statements(MySQL, s, sqlgen.ActionLookup)
+
+		// users.email and tags.name are unique
+		if len(lookupStmts) != 2 {
+			t.Errorf("expected 2 lookup statements, got %d", len(lookupStmts))
+		}
+	})
+
+	// Test foreign key list generation
+	t.Run("TestGetListByForeignKeyStatements", func(t *testing.T) {
+		database := "testdb"
+
+		s, err := mysql.NewIntrospector(db).Introspect(ctx, sqlgen.IntrospectOptions{Schema: database})
+		if err != nil {
+			t.Fatalf("failed to get schema: %s", err)
+		}
+		listStmts := statements(MySQL, s, sqlgen.ActionListByFK)
+
+		if len(listStmts) != 1 || !strings.Contains(strings.ToLower(listStmts[0]), "from posts where user_id =") {
+			t.Errorf("expected a list statement for posts.user_id, got %v", listStmts)
+		}
+	})
+
+	// Test upsert generation
+	t.Run("TestGetUpsertStatements", func(t *testing.T) {
+		database := "testdb"
+
+		s, err := mysql.NewIntrospector(db).Introspect(ctx, sqlgen.IntrospectOptions{Schema: database})
+		if err != nil {
+			t.Fatalf("failed to get schema: %s", err)
+		}
+		upsertStmts := statements(MySQL, s, sqlgen.ActionUpsert)
+
+		if len(upsertStmts) != 3 {
+			t.Errorf("expected 3 upsert statements, got %d", len(upsertStmts))
+		}
+		for _, stmt := range upsertStmts {
+			if !strings.Contains(strings.ToLower(stmt), "on duplicate key update") {
+				t.Errorf("unexpected upsert statement: %s", stmt)
+			}
+		}
+	})
+
 	// Test skip tables functionality
 	t.Run("TestSkipTables", func(t *testing.T) {
 		database := "testdb"
 
 		// Skip the tags table
 		skipTables := []string{"tags"}
-		insertStmts, err := getInsertsStmts(ctx, db, database, skipTables)
+		s, err := mysql.NewIntrospector(db).Introspect(ctx, sqlgen.IntrospectOptions{Schema: database, SkipTables: skipTables})
 		if err != nil {
-			t.Fatalf("failed to get insert statements with skip: %s", err)
+			t.Fatalf("failed to get schema with skip: %s", err)
 		}
+		insertStmts := statements(MySQL, s, sqlgen.ActionCreate)
 
 		// Should only have 2 statements (users and posts)
 		if len(insertStmts) != 2 {
@@ -181,34 +257,3 @@ func TestMySQLIntegration(t *testing.T) {
 		}
 	})
 }
-
-func TestSqlcCommentGeneration(t *testing.T) {
-	testCases := []struct {
-		name     string
-		sql      string
-		action   string
-		expected string
-	}{
-		{
-			name:     "Create user comment",
-			sql:      "INSERT INTO users (name, email) VALUES (?, ?)",
-			action:   "create",
-			expected: "-- name: CreateUser :exec",
-		},
-		{
-			name:     "Read post comment",
-			sql:      "SELECT * FROM posts WHERE id = ?",
-			action:   "read",
-			expected: "-- name: GetPostByPk :one",
-		},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			comment := genComment4Sqlc(tc.sql, tc.action)
-			if comment != tc.expected {
-				t.Errorf("expected comment %q, got %q", tc.expected, comment)
-			}
-		})
-	}
-}
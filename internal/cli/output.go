@@ -0,0 +1,202 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/miyataka/sqlgen"
+	"github.com/miyataka/sqlgen/internal/diff"
+)
+
+// goPackageName matches the directory names usable as package names.
+var goPackageName = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// defaultFilenamePattern names the file of each table under --out-dir.
+const defaultFilenamePattern = "{table}.sql"
+
+// outputFile is a generated file, its content on disk and the content with
+// its generated regions updated. The file is removed when content is empty.
+type outputFile struct {
+	path    string
+	current string
+	content string
+}
+
+// files returns one file per table under o.outDir holding the queries of
+// the table, named by o.filenamePattern. The queries of an existing file
+// are updated in place and everything else in it is kept. Files matching
+// the pattern whose table is no longer generated lose their regions.
+func (o *options) files(queries []sqlgen.Query) ([]outputFile, error) {
+	if !strings.Contains(o.filenamePattern, "{table}") {
+		return nil, fmt.Errorf("filename pattern %q does not contain {table}", o.filenamePattern)
+	}
+	var files []outputFile
+	tables := make(map[string]*sqlgen.Table)
+	for _, group := range sqlgen.GroupByTable(queries) {
+		t := group[0].Table
+		name := strings.NewReplacer("{schema}", t.Schema, "{table}", t.Name).Replace(o.filenamePattern)
+		path := filepath.Join(o.outDir, name)
+		if other, ok := tables[path]; ok {
+			return nil, fmt.Errorf("tables %s.%s and %s.%s are both written to %s; add {schema} to the filename pattern", other.Schema, other.Name, t.Schema, t.Name, path)
+		}
+		tables[path] = t
+		current, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		content, err := updateRegions(string(current), group, o.sqlc)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		files = append(files, outputFile{path: path, current: string(current), content: content})
+	}
+	stale, err := o.staleFiles(tables)
+	if err != nil {
+		return nil, err
+	}
+	return append(files, stale...), nil
+}
+
+// staleFiles returns the files under o.outDir matching o.filenamePattern
+// that are not in generated but hold generated regions, with the regions
+// removed. Files left without content are removed.
+func (o *options) staleFiles(generated map[string]*sqlgen.Table) ([]outputFile, error) {
+	glob := strings.NewReplacer("{schema}", "*", "{table}", "*").Replace(o.filenamePattern)
+	var files []outputFile
+	err := filepath.WalkDir(o.outDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || generated[path] != nil {
+			return err
+		}
+		rel, err := filepath.Rel(o.outDir, path)
+		if err != nil {
+			return err
+		}
+		if ok, err := filepath.Match(glob, rel); !ok || err != nil {
+			return err
+		}
+		current, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		content, err := updateRegions(string(current), nil, o.sqlc)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if content == string(current) {
+			return nil
+		}
+		if strings.TrimSpace(content) == "" {
+			content = ""
+		}
+		files = append(files, outputFile{path: path, current: string(current), content: content})
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return files, err
+}
+
+// goFiles returns the Go file at o.goOut declaring the models of the tables
+// of schema and, with o.goRepo set, the one with the functions running
+// queries.
+func (o *options) goFiles(g *sqlgen.Generator, schema *sqlgen.Schema, queries []sqlgen.Query) ([]outputFile, error) {
+	pkg := o.goPackage
+	if pkg == "" {
+		var err error
+		if pkg, err = packageName(filepath.Dir(o.goOut)); err != nil {
+			return nil, err
+		}
+	}
+	models := g.Models(schema)
+	f, err := goFile(o.goOut, func(w io.Writer) error { return sqlgen.WriteModels(w, pkg, models) })
+	if err != nil {
+		return nil, err
+	}
+	files := []outputFile{f}
+	if o.goRepo != "" {
+		f, err := goFile(o.goRepo, func(w io.Writer) error { return g.WriteRepository(w, pkg, queries, models) })
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+// goFile returns the file at path with the content written by write.
+func goFile(path string, write func(io.Writer) error) (outputFile, error) {
+	current, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return outputFile{}, err
+	}
+	var content strings.Builder
+	if err := write(&content); err != nil {
+		return outputFile{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return outputFile{path: path, current: string(current), content: content.String()}, nil
+}
+
+// packageName returns the Go package name matching the name of dir, e.g.
+// "db" for internal/db, or "models" if the name cannot be one.
+func packageName(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	name := strings.ToLower(filepath.Base(abs))
+	if !goPackageName.MatchString(name) || token.IsKeyword(name) {
+		return "models", nil
+	}
+	return name, nil
+}
+
+// writeFiles writes the files that changed, creating their directories as
+// needed, and removes the ones left empty.
+func writeFiles(files []outputFile) error {
+	for _, f := range files {
+		if f.content == f.current {
+			continue
+		}
+		if f.content == "" {
+			if err := os.Remove(f.path); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(f.path, []byte(f.content), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkFiles writes the diff between files and their content on disk to w.
+// It fails when a file is missing or differs.
+func checkFiles(w io.Writer, files []outputFile) error {
+	var stale []string
+	for _, f := range files {
+		d := diff.Unified(f.path, f.path+" (generated)", f.current, f.content)
+		if d == "" {
+			continue
+		}
+		stale = append(stale, f.path)
+		if _, err := io.WriteString(w, d); err != nil {
+			return err
+		}
+	}
+	if len(stale) > 0 {
+		return fmt.Errorf("generated files are out of date in %s", strings.Join(stale, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,238 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOutDir(t *testing.T) {
+	dir := t.TempDir()
+	schemaFile := filepath.Join(dir, "schema.sql")
+	ddl := "CREATE TABLE users (id serial PRIMARY KEY, name text NOT NULL);\n" +
+		"CREATE TABLE posts (id serial PRIMARY KEY, title text NOT NULL);\n"
+	if err := os.WriteFile(schemaFile, []byte(ddl), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	outDir := filepath.Join(dir, "queries")
+
+	cmd := NewDriverCommand("psqlgen", Postgres)
+	cmd.SetArgs([]string{"--schema-file", schemaFile, "--actions", "create,read", "--out-dir", outDir, "--filename-pattern", "{schema}/{table}_queries.sql"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]string{
+		"public/posts_queries.sql": "-- sqlgen:begin CreatePost\nINSERT INTO posts (title) VALUES ($1) RETURNING *;\n-- sqlgen:end\n\n" +
+			"-- sqlgen:begin GetPostByPk\nSELECT id, title FROM posts WHERE id = $1;\n-- sqlgen:end\n",
+		"public/users_queries.sql": "-- sqlgen:begin CreateUser\nINSERT INTO users (name) VALUES ($1) RETURNING *;\n-- sqlgen:end\n\n" +
+			"-- sqlgen:begin GetUserByPk\nSELECT id, name FROM users WHERE id = $1;\n-- sqlgen:end\n",
+	}
+	for name, want := range expected {
+		got, err := os.ReadFile(filepath.Join(outDir, name))
+		if err != nil {
+			t.Errorf("missing %s: %v", name, err)
+			continue
+		}
+		if string(got) != want {
+			t.Errorf("%s: expected %q, got %q", name, want, got)
+		}
+	}
+}
+
+func TestFilenamePatternWithoutTable(t *testing.T) {
+	o := &options{outDir: "queries", filenamePattern: "queries.sql"}
+	if _, err := o.files(nil); err == nil {
+		t.Error("expected error for a pattern without {table}")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	schemaFile := filepath.Join(dir, "schema.sql")
+	writeSchema := func(ddl string) {
+		if err := os.WriteFile(schemaFile, []byte(ddl), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	outDir := filepath.Join(dir, "queries")
+	run := func(args ...string) (string, error) {
+		var stdout strings.Builder
+		cmd := NewDriverCommand("psqlgen", Postgres)
+		cmd.SetOut(&stdout)
+		cmd.SetArgs(append([]string{"--schema-file", schemaFile, "--actions", "read", "--out-dir", outDir}, args...))
+		err := cmd.Execute()
+		return stdout.String(), err
+	}
+
+	writeSchema("CREATE TABLE users (id serial PRIMARY KEY, name text NOT NULL);")
+	if _, err := run(); err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	if out, err := run("--check"); err != nil || out != "" {
+		t.Errorf("expected up to date files, got %q, %v", out, err)
+	}
+
+	writeSchema("CREATE TABLE users (id serial PRIMARY KEY, email text NOT NULL);")
+	out, err := run("--check")
+	if err == nil || !strings.Contains(err.Error(), "out of date") {
+		t.Errorf("expected out of date error, got %v", err)
+	}
+	path := filepath.Join(outDir, "users.sql")
+	expected := "--- " + path + "\n+++ " + path + " (generated)\n@@ -1,3 +1,3 @@\n -- sqlgen:begin GetUserByPk\n" +
+		"-SELECT id, name FROM users WHERE id = $1;\n+SELECT id, email FROM users WHERE id = $1;\n -- sqlgen:end\n"
+	if out != expected {
+		t.Errorf("expected %q, got %q", expected, out)
+	}
+	if got, _ := os.ReadFile(path); !strings.Contains(string(got), "name") {
+		t.Errorf("expected --check to leave %s unchanged, got %q", path, got)
+	}
+}
+
+func TestStaleFiles(t *testing.T) {
+	dir := t.TempDir()
+	schemaFile := filepath.Join(dir, "schema.sql")
+	writeSchema := func(ddl string) {
+		if err := os.WriteFile(schemaFile, []byte(ddl), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	outDir := filepath.Join(dir, "queries")
+	run := func(args ...string) error {
+		cmd := NewDriverCommand("psqlgen", Postgres)
+		cmd.SetOut(&strings.Builder{})
+		cmd.SetArgs(append([]string{"--schema-file", schemaFile, "--actions", "read", "--out-dir", outDir}, args...))
+		return cmd.Execute()
+	}
+
+	writeSchema("CREATE TABLE users (id int PRIMARY KEY);\n" +
+		"CREATE TABLE posts (id int PRIMARY KEY);\n" +
+		"CREATE TABLE tags (id int PRIMARY KEY);\n")
+	if err := run(); err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	posts := filepath.Join(outDir, "posts.sql")
+	current, err := os.ReadFile(posts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handWritten := "-- name: CountPosts :one\nSELECT count(*) FROM posts;\n"
+	if err := os.WriteFile(posts, []byte(handWritten+"\n"+string(current)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	notes := filepath.Join(outDir, "notes.txt")
+	if err := os.WriteFile(notes, []byte("-- sqlgen:begin GetNoteByPk\n-- sqlgen:end\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	writeSchema("CREATE TABLE users (id int PRIMARY KEY);")
+	err = run("--check")
+	if err == nil || !strings.Contains(err.Error(), posts) || !strings.Contains(err.Error(), filepath.Join(outDir, "tags.sql")) {
+		t.Errorf("expected posts.sql and tags.sql to be out of date, got %v", err)
+	}
+
+	if err := run(); err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	if got, _ := os.ReadFile(posts); string(got) != handWritten {
+		t.Errorf("expected %q, got %q", handWritten, got)
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "tags.sql")); !os.IsNotExist(err) {
+		t.Errorf("expected tags.sql to be removed, got %v", err)
+	}
+	if _, err := os.Stat(notes); err != nil {
+		t.Errorf("expected a file not matching the pattern to be kept, got %v", err)
+	}
+	if err := run("--check"); err != nil {
+		t.Errorf("expected up to date files, got %v", err)
+	}
+}
+
+func TestCheckRequiresOutDir(t *testing.T) {
+	dir := t.TempDir()
+	schemaFile := filepath.Join(dir, "schema.sql")
+	if err := os.WriteFile(schemaFile, []byte("CREATE TABLE users (id int PRIMARY KEY);"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := NewDriverCommand("psqlgen", Postgres)
+	cmd.SetOut(&strings.Builder{})
+	cmd.SetArgs([]string{"--schema-file", schemaFile, "--check"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--check requires --out-dir") {
+		t.Errorf("expected --out-dir error, got %v", err)
+	}
+}
+
+func TestGoOut(t *testing.T) {
+	dir := t.TempDir()
+	schemaFile := filepath.Join(dir, "schema.sql")
+	if err := os.WriteFile(schemaFile, []byte("CREATE TABLE users (id serial PRIMARY KEY, name text);"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	goOut := filepath.Join(dir, "db", "models.go")
+	run := func(args ...string) (string, error) {
+		var stdout strings.Builder
+		cmd := NewDriverCommand("psqlgen", Postgres)
+		cmd.SetOut(&stdout)
+		cmd.SetArgs(append([]string{"--schema-file", schemaFile, "--actions", "read", "--go-out", goOut}, args...))
+		err := cmd.Execute()
+		return stdout.String(), err
+	}
+
+	out, err := run()
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	if expected := "SELECT id, name FROM users WHERE id = $1;\n"; out != expected {
+		t.Errorf("expected %q, got %q", expected, out)
+	}
+	got, err := os.ReadFile(goOut)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "// Code generated by sqlgen. DO NOT EDIT.\n\npackage db\n\nimport \"database/sql\"\n\n" +
+		"// User is a row of users.\ntype User struct {\n\tID   int32          `db:\"id\"`\n\tName sql.NullString `db:\"name\"`\n}\n"
+	if string(got) != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+
+	if out, err := run("--check"); err != nil || out != "" {
+		t.Errorf("expected up to date models, got %q, %v", out, err)
+	}
+	if _, err := run("--check", "--go-null-pointers"); err == nil || !strings.Contains(err.Error(), "out of date in "+goOut) {
+		t.Errorf("expected out of date error, got %v", err)
+	}
+}
+
+func TestGoRepo(t *testing.T) {
+	dir := t.TempDir()
+	schemaFile := filepath.Join(dir, "schema.sql")
+	if err := os.WriteFile(schemaFile, []byte("CREATE TABLE users (id serial PRIMARY KEY, name text);"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	goOut := filepath.Join(dir, "db", "models.go")
+	goRepo := filepath.Join(dir, "db", "queries.go")
+
+	cmd := NewDriverCommand("psqlgen", Postgres)
+	cmd.SetOut(&strings.Builder{})
+	cmd.SetArgs([]string{"--schema-file", schemaFile, "--actions", "delete", "--go-out", goOut, "--go-repo", goRepo})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	got, err := os.ReadFile(goRepo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"package db\n", "const deleteUser = `DELETE FROM users WHERE id = $1;`\n", "func DeleteUser(ctx context.Context, db DBTX, id int32) (int64, error) {\n"} {
+		if !strings.Contains(string(got), expected) {
+			t.Errorf("expected %s to contain %q, got %q", goRepo, expected, got)
+		}
+	}
+
+	cmd = NewDriverCommand("psqlgen", Postgres)
+	cmd.SetOut(&strings.Builder{})
+	cmd.SetArgs([]string{"--schema-file", schemaFile, "--go-out", goOut, "--go-repo", filepath.Join(dir, "queries.go")})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--go-repo requires --go-out in the same directory") {
+		t.Errorf("expected --go-out error, got %v", err)
+	}
+}
@@ -1,4 +1,4 @@
-package main
+package cli
 
 import (
 	"context"
@@ -7,19 +7,21 @@ import (
 	"testing"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/miyataka/sqlgen"
+	"github.com/miyataka/sqlgen/postgres"
 	"github.com/testcontainers/testcontainers-go"
-	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
 	"github.com/testcontainers/testcontainers-go/wait"
 )
 
 func TestPostgresIntegration(t *testing.T) {
 	ctx := context.Background()
 
-	postgresContainer, err := postgres.Run(ctx,
+	postgresContainer, err := tcpostgres.Run(ctx,
 		"postgres:16-alpine",
-		postgres.WithDatabase("testdb"),
-		postgres.WithUsername("postgres"),
-		postgres.WithPassword("postgres"),
+		tcpostgres.WithDatabase("testdb"),
+		tcpostgres.WithUsername("postgres"),
+		tcpostgres.WithPassword("postgres"),
 		testcontainers.WithWaitStrategy(
 			wait.ForLog("database system is ready to accept connections").
 				WithOccurrence(2)),
@@ -72,15 +74,16 @@ func TestPostgresIntegration(t *testing.T) {
 		t.Fatalf("failed to create tables: %s", err)
 	}
 
-	// Test getInsertsStmts
+	// Test INSERT generation
 	t.Run("TestGetInsertStatements", func(t *testing.T) {
 		// Use "public" schema for PostgreSQL
 		schema := "public"
 
-		insertStmts, err := getInsertsStmts(ctx, db, schema, []string{})
+		s, err := postgres.NewIntrospector(db).Introspect(ctx, sqlgen.IntrospectOptions{Schema: schema})
 		if err != nil {
-			t.Fatalf("failed to get insert statements: %s", err)
+			t.Fatalf("failed to get schema: %s", err)
 		}
+		insertStmts := statements(Postgres, s, sqlgen.ActionCreate)
 
 		if len(insertStmts) != 3 {
 			t.Errorf("expected 3 insert statements, got %d", len(insertStmts))
@@ -114,15 +117,16 @@ func TestPostgresIntegration(t *testing.T) {
 		}
 	})
 
-	// Test getSelectByPkStmts
+	// Test SELECT generation
 	t.Run("TestGetSelectStatements", func(t *testing.T) {
 		// Use "public" schema for PostgreSQL
 		schema := "public"
 
-		selectStmts, err := getSelectByPkStmts(ctx, db, schema, []string{})
+		s, err := postgres.NewIntrospector(db).Introspect(ctx, sqlgen.IntrospectOptions{Schema: schema})
 		if err != nil {
-			t.Fatalf("failed to get select statements: %s", err)
+			t.Fatalf("failed to get schema: %s", err)
 		}
+		selectStmts := statements(Postgres, s, sqlgen.ActionRead)
 
 		if len(selectStmts) != 3 {
 			t.Errorf("expected 3 select statements, got %d", len(selectStmts))
@@ -156,6 +160,77 @@ func TestPostgresIntegration(t *testing.T) {
 		}
 	})
 
+	// Test UPDATE generation
+	t.Run("TestGetUpdateStatements", func(t *testing.T) {
+		schema := "public"
+
+		s, err := postgres.NewIntrospector(db).Introspect(ctx, sqlgen.IntrospectOptions{Schema: schema})
+		if err != nil {
+			t.Fatalf("failed to get schema: %s", err)
+		}
+		updateStmts := statements(Postgres, s, sqlgen.ActionUpdate)
+
+		if len(updateStmts) != 3 {
+			t.Errorf("expected 3 update statements, got %d", len(updateStmts))
+		}
+		for _, stmt := range updateStmts {
+			if strings.Contains(strings.ToLower(stmt), "set id =") {
+				t.Errorf("primary key must not be updated: %s", stmt)
+			}
+		}
+	})
+
+	// Test unique lookup generation
+	t.Run("TestGetLookupStatements", func(t *testing.T) {
+		schema := "public"
+
+		s, err := postgres.NewIntrospector(db).Introspect(ctx, sqlgen.IntrospectOptions{Schema: schema})
+		if err != nil {
+			t.Fatalf("failed to get schema: %s", err)
+		}
+		lookupStmts := statements(Postgres, s, sqlgen.ActionLookup)
+
+		// users.email and tags.name are unique
+		if len(lookupStmts) != 2 {
+			t.Errorf("expected 2 lookup statements, got %d", len(lookupStmts))
+		}
+	})
+
+	// Test foreign key list generation
+	t.Run("TestGetListByForeignKeyStatements", func(t *testing.T) {
+		schema := "public"
+
+		s, err := postgres.NewIntrospector(db).Introspect(ctx, sqlgen.IntrospectOptions{Schema: schema})
+		if err != nil {
+			t.Fatalf("failed to get schema: %s", err)
+		}
+		listStmts := statements(Postgres, s, sqlgen.ActionListByFK)
+
+		if len(listStmts) != 1 || !strings.Contains(strings.ToLower(listStmts[0]), "from posts where user_id =") {
+			t.Errorf("expected a list statement for posts.user_id, got %v", listStmts)
+		}
+	})
+
+	// Test upsert generation
+	t.Run("TestGetUpsertStatements", func(t *testing.T) {
+		schema := "public"
+
+		s, err := postgres.NewIntrospector(db).Introspect(ctx, sqlgen.IntrospectOptions{Schema: schema})
+		if err != nil {
+			t.Fatalf("failed to get schema: %s", err)
+		}
+		upsertStmts := statements(Postgres, s, sqlgen.ActionUpsert)
+
+		if len(upsertStmts) != 3 {
+			t.Errorf("expected 3 upsert statements, got %d", len(upsertStmts))
+		}
+		for _, stmt := range upsertStmts {
+			if !strings.Contains(strings.ToLower(stmt), "on conflict (id) do update") {
+				t.Errorf("unexpected upsert statement: %s", stmt)
+			}
+		}
+	})
+
 	// Test skip tables functionality
 	t.Run("TestSkipTables", func(t *testing.T) {
 		// Use "public" schema for PostgreSQL
@@ -163,10 +238,11 @@ func TestPostgresIntegration(t *testing.T) {
 
 		// Skip the tags table
 		skipTables := []string{"tags"}
-		insertStmts, err := getInsertsStmts(ctx, db, schema, skipTables)
+		s, err := postgres.NewIntrospector(db).Introspect(ctx, sqlgen.IntrospectOptions{Schema: schema, SkipTables: skipTables})
 		if err != nil {
-			t.Fatalf("failed to get insert statements with skip: %s", err)
+			t.Fatalf("failed to get schema with skip: %s", err)
 		}
+		insertStmts := statements(Postgres, s, sqlgen.ActionCreate)
 
 		// Should only have 2 statements (users and posts)
 		if len(insertStmts) != 2 {
@@ -181,34 +257,3 @@ func TestPostgresIntegration(t *testing.T) {
 		}
 	})
 }
-
-func TestSqlcCommentGeneration(t *testing.T) {
-	testCases := []struct {
-		name     string
-		sql      string
-		action   string
-		expected string
-	}{
-		{
-			name:     "Create user comment",
-			sql:      "INSERT INTO users (name, email) VALUES ($1, $2)",
-			action:   "create",
-			expected: "-- name: CreateUser :one",
-		},
-		{
-			name:     "Read post comment",
-			sql:      "SELECT * FROM posts WHERE id = $1",
-			action:   "read",
-			expected: "-- name: GetPostByPk :one",
-		},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			comment := genComment4Sqlc(tc.sql, tc.action)
-			if comment != tc.expected {
-				t.Errorf("expected comment %q, got %q", tc.expected, comment)
-			}
-		})
-	}
-}
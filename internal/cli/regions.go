@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/miyataka/sqlgen"
+)
+
+// Markers around the generated region of a query in files under --out-dir,
+// e.g. "-- sqlgen:begin CreateUser" and "-- sqlgen:end". Everything outside
+// the regions is written by hand and kept as is.
+const (
+	beginMarker = "sqlgen:begin"
+	endMarker   = "sqlgen:end"
+)
+
+// segment is a part of a file: the generated region of the query name, or
+// hand-written text when name is empty.
+type segment struct {
+	name string
+	text string
+}
+
+// updateRegions returns content with the region of each query replaced by
+// its current SQL. Regions of queries no longer generated are removed, and
+// regions of new queries are added after the region of the query generated
+// before them.
+func updateRegions(content string, queries []sqlgen.Query, sqlc bool) (string, error) {
+	segments, err := parseRegions(content)
+	if err != nil {
+		return "", err
+	}
+	regions := make(map[string]string, len(queries))
+	for _, q := range queries {
+		if _, ok := regions[q.Name]; ok {
+			return "", fmt.Errorf("query %s is generated twice", q.Name)
+		}
+		regions[q.Name] = region(q, sqlc)
+	}
+
+	var updated []segment
+	trimNext := false
+	for _, s := range segments {
+		if s.name == "" {
+			if trimNext {
+				s.text = strings.TrimPrefix(s.text, "\n")
+			}
+			trimNext = false
+			updated = append(updated, s)
+			continue
+		}
+		text, ok := regions[s.name]
+		if ok {
+			updated = append(updated, segment{name: s.name, text: text})
+			trimNext = false
+			continue
+		}
+		// drop the region together with the blank line separating it
+		if n := len(updated); n > 0 && updated[n-1].name == "" && strings.HasSuffix(updated[n-1].text, "\n\n") {
+			updated[n-1].text = strings.TrimSuffix(updated[n-1].text, "\n")
+		} else {
+			trimNext = true
+		}
+	}
+
+	prev := -1
+	for _, q := range queries {
+		if i := regionIndex(updated, q.Name); i >= 0 {
+			prev = i
+			continue
+		}
+		s := segment{name: q.Name, text: regions[q.Name]}
+		switch first := regionIndex(updated, ""); {
+		case prev >= 0:
+			updated = insert(updated, prev+1, segment{text: "\n"}, s)
+			prev += 2
+		case first >= 0:
+			updated = insert(updated, first, s, segment{text: "\n"})
+			prev = first
+		default:
+			if text := join(updated); text != "" {
+				sep := "\n"
+				if !strings.HasSuffix(text, "\n") {
+					sep = "\n\n"
+				} else if strings.HasSuffix(text, "\n\n") {
+					sep = ""
+				}
+				updated = append(updated, segment{text: sep})
+			}
+			updated = append(updated, s)
+			prev = len(updated) - 1
+		}
+	}
+	return join(updated), nil
+}
+
+// parseRegions splits content into hand-written text and generated regions.
+func parseRegions(content string) ([]segment, error) {
+	var segments []segment
+	var text strings.Builder
+	name, begin := "", 0
+	flush := func(name string) {
+		if text.Len() > 0 {
+			segments = append(segments, segment{name: name, text: text.String()})
+			text.Reset()
+		}
+	}
+	seen := map[string]bool{}
+	for i, line := range strings.SplitAfter(content, "\n") {
+		kind, arg := marker(line)
+		switch kind {
+		case beginMarker:
+			if name != "" {
+				return nil, fmt.Errorf("line %d: %s inside the region of %s", i+1, beginMarker, name)
+			}
+			if arg == "" {
+				return nil, fmt.Errorf("line %d: %s without a query name", i+1, beginMarker)
+			}
+			if seen[arg] {
+				return nil, fmt.Errorf("line %d: duplicate region of %s", i+1, arg)
+			}
+			seen[arg] = true
+			flush("")
+			name, begin = arg, i+1
+			text.WriteString(line)
+		case endMarker:
+			if name == "" {
+				return nil, fmt.Errorf("line %d: %s without %s", i+1, endMarker, beginMarker)
+			}
+			text.WriteString(line)
+			flush(name)
+			name = ""
+		default:
+			text.WriteString(line)
+		}
+	}
+	if name != "" {
+		return nil, fmt.Errorf("line %d: %s %s without %s", begin, beginMarker, name, endMarker)
+	}
+	flush("")
+	return segments, nil
+}
+
+// marker returns the marker and its argument if line is a marker comment.
+func marker(line string) (kind, arg string) {
+	comment, ok := strings.CutPrefix(strings.TrimSpace(line), "--")
+	if !ok {
+		return "", ""
+	}
+	fields := strings.Fields(comment)
+	if len(fields) == 0 || fields[0] != beginMarker && fields[0] != endMarker {
+		return "", ""
+	}
+	return fields[0], strings.Join(fields[1:], " ")
+}
+
+// region renders the generated region of q.
+func region(q sqlgen.Query, sqlc bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "-- %s %s\n", beginMarker, q.Name)
+	if sqlc {
+		b.WriteString(q.Comment() + "\n")
+	}
+	b.WriteString(q.SQL + "\n")
+	fmt.Fprintf(&b, "-- %s\n", endMarker)
+	return b.String()
+}
+
+// regionIndex returns the index of the region of name, or of the first
+// region when name is empty, or -1.
+func regionIndex(segments []segment, name string) int {
+	for i, s := range segments {
+		if s.name != "" && (name == "" || s.name == name) {
+			return i
+		}
+	}
+	return -1
+}
+
+func insert(segments []segment, i int, s ...segment) []segment {
+	return append(segments[:i], append(s, segments[i:]...)...)
+}
+
+func join(segments []segment) string {
+	var b strings.Builder
+	for _, s := range segments {
+		b.WriteString(s.text)
+	}
+	return b.String()
+}
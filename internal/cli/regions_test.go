@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/miyataka/sqlgen"
+)
+
+func TestUpdateRegions(t *testing.T) {
+	create := sqlgen.Query{Name: "CreateUser", Cmd: ":one", SQL: "INSERT INTO users (name) VALUES ($1) RETURNING *;"}
+	get := sqlgen.Query{Name: "GetUserByPk", Cmd: ":one", SQL: "SELECT id, name FROM users WHERE id = $1;"}
+	list := sqlgen.Query{Name: "ListUsers", Cmd: ":many", SQL: "SELECT id, name FROM users;"}
+
+	tests := []struct {
+		name     string
+		content  string
+		queries  []sqlgen.Query
+		sqlc     bool
+		expected string
+	}{
+		{
+			name:    "new file",
+			queries: []sqlgen.Query{create, get},
+			sqlc:    true,
+			expected: "-- sqlgen:begin CreateUser\n-- name: CreateUser :one\nINSERT INTO users (name) VALUES ($1) RETURNING *;\n-- sqlgen:end\n\n" +
+				"-- sqlgen:begin GetUserByPk\n-- name: GetUserByPk :one\nSELECT id, name FROM users WHERE id = $1;\n-- sqlgen:end\n",
+		},
+		{
+			name: "hand-written queries are kept",
+			content: "-- name: CountUsers :one\nSELECT count(*) FROM users;\n\n" +
+				"-- sqlgen:begin GetUserByPk\nSELECT id FROM users WHERE id = $1;\n-- sqlgen:end\n\n" +
+				"-- name: SearchUsers :many\nSELECT * FROM users WHERE name LIKE $1;\n",
+			queries: []sqlgen.Query{get},
+			expected: "-- name: CountUsers :one\nSELECT count(*) FROM users;\n\n" +
+				"-- sqlgen:begin GetUserByPk\nSELECT id, name FROM users WHERE id = $1;\n-- sqlgen:end\n\n" +
+				"-- name: SearchUsers :many\nSELECT * FROM users WHERE name LIKE $1;\n",
+		},
+		{
+			name: "region of a query no longer generated is removed",
+			content: "-- sqlgen:begin CreateUser\nINSERT INTO users (name) VALUES ($1) RETURNING *;\n-- sqlgen:end\n\n" +
+				"-- sqlgen:begin GetUserByEmail\nSELECT id, name FROM users WHERE email = $1;\n-- sqlgen:end\n\n" +
+				"-- sqlgen:begin GetUserByPk\nSELECT id, name FROM users WHERE id = $1;\n-- sqlgen:end\n",
+			queries: []sqlgen.Query{create, get},
+			expected: "-- sqlgen:begin CreateUser\nINSERT INTO users (name) VALUES ($1) RETURNING *;\n-- sqlgen:end\n\n" +
+				"-- sqlgen:begin GetUserByPk\nSELECT id, name FROM users WHERE id = $1;\n-- sqlgen:end\n",
+		},
+		{
+			name: "new queries are added next to their neighbours",
+			content: "-- custom\n\n" +
+				"-- sqlgen:begin GetUserByPk\nSELECT id, name FROM users WHERE id = $1;\n-- sqlgen:end\n\n" +
+				"-- more custom\n",
+			queries: []sqlgen.Query{create, get, list},
+			expected: "-- custom\n\n" +
+				"-- sqlgen:begin CreateUser\nINSERT INTO users (name) VALUES ($1) RETURNING *;\n-- sqlgen:end\n\n" +
+				"-- sqlgen:begin GetUserByPk\nSELECT id, name FROM users WHERE id = $1;\n-- sqlgen:end\n\n" +
+				"-- sqlgen:begin ListUsers\nSELECT id, name FROM users;\n-- sqlgen:end\n\n" +
+				"-- more custom\n",
+		},
+		{
+			name:    "regions are appended to hand-written files",
+			content: "SELECT 1;",
+			queries: []sqlgen.Query{list},
+			expected: "SELECT 1;\n\n" +
+				"-- sqlgen:begin ListUsers\nSELECT id, name FROM users;\n-- sqlgen:end\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := updateRegions(tt.content, tt.queries, tt.sqlc)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+			again, err := updateRegions(got, tt.queries, tt.sqlc)
+			if err != nil || again != got {
+				t.Errorf("expected a second run to change nothing, got %q, %v", again, err)
+			}
+		})
+	}
+}
+
+func TestUpdateRegionsErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"unterminated", "SELECT 1;\n-- sqlgen:begin ListUsers\nSELECT 2;\n", "line 2: sqlgen:begin ListUsers without sqlgen:end"},
+		{"end without begin", "SELECT 1;\n-- sqlgen:end\n", "line 2: sqlgen:end without sqlgen:begin"},
+		{"nested", "-- sqlgen:begin A\n-- sqlgen:begin B\n", "line 2: sqlgen:begin inside the region of A"},
+		{"duplicate", "-- sqlgen:begin A\n-- sqlgen:end\n-- sqlgen:begin A\n-- sqlgen:end\n", "line 3: duplicate region of A"},
+		{"missing name", "-- sqlgen:begin\n-- sqlgen:end\n", "line 1: sqlgen:begin without a query name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := updateRegions(tt.content, nil, false)
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("expected %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
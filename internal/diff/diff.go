@@ -0,0 +1,216 @@
+// Package diff computes line-based unified diffs.
+package diff
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// context is the number of unchanged lines shown around a change.
+const context = 3
+
+// edit is one line of an edit script: kept (' '), deleted ('-') or
+// inserted ('+').
+type edit struct {
+	kind byte
+	line string
+}
+
+// Unified returns the unified diff turning a into b, labelled with the file
+// names aName and bName, or "" when a and b are equal.
+func Unified(aName, bName, a, b string) string {
+	if a == b {
+		return ""
+	}
+	edits := lineEdits(splitLines(a), splitLines(b))
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", aName, bName)
+	for start := 0; start < len(edits); {
+		first := nextChange(edits, start)
+		if first < 0 {
+			break
+		}
+		// extend the hunk while the next change is close enough to share context
+		last := first
+		for {
+			next := nextChange(edits, last+1)
+			if next < 0 || next-last > 2*context {
+				break
+			}
+			last = next
+		}
+		from := max(first-context, 0)
+		to := min(last+context+1, len(edits))
+		writeHunk(&out, edits, from, to)
+		start = to
+	}
+	return out.String()
+}
+
+// splitLines splits text into lines, marking a last line without newline.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	} else {
+		lines[len(lines)-1] += "\n\\ No newline at end of file\n"
+	}
+	return lines
+}
+
+// maxDistance bounds the edit distance searched for a shortest edit script.
+// Inputs further apart are diffed by replacing all their differing lines,
+// keeping the memory used linear in their length.
+const maxDistance = 1000
+
+// lineEdits returns the edit script turning a into b. It keeps the lines
+// they start and end with and diffs the lines between with myers.
+func lineEdits(a, b []string) []edit {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	edits := make([]edit, 0, max(len(a), len(b)))
+	for _, line := range a[:prefix] {
+		edits = append(edits, edit{' ', line})
+	}
+	edits = append(edits, myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		edits = append(edits, edit{' ', line})
+	}
+	return edits
+}
+
+// myers returns the shortest edit script turning a into b, or one deleting
+// all of a and inserting all of b if it needs more than maxDistance edits.
+func myers(a, b []string) []edit {
+	n, m := len(a), len(b)
+	limit := min(n+m, maxDistance)
+	// v[offset+k] is the furthest x reached on diagonal k = x-y
+	offset := limit + 1
+	v := make([]int, 2*limit+3)
+	// trace[d] holds v[offset-d-1:offset+d+2] before round d
+	var trace [][]int
+	for d := 0; d <= limit; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || k != d && v[offset+k-1] < v[offset+k+1] {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(a, b, trace)
+			}
+		}
+	}
+
+	edits := make([]edit, 0, n+m)
+	for _, line := range a {
+		edits = append(edits, edit{'-', line})
+	}
+	for _, line := range b {
+		edits = append(edits, edit{'+', line})
+	}
+	return edits
+}
+
+// backtrack follows the furthest reaching paths recorded by myers back from
+// the end of a and b and returns the edit script they make.
+func backtrack(a, b []string, trace [][]int) []edit {
+	x, y := len(a), len(b)
+	var reversed []edit
+	for d := len(trace) - 1; d >= 0; d-- {
+		v, offset := trace[d], d+1
+		k := x - y
+		prevK := k - 1
+		if k == -d || k != d && v[offset+k-1] < v[offset+k+1] {
+			prevK = k + 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			reversed = append(reversed, edit{' ', a[x]})
+		}
+		if d == 0 {
+			break
+		}
+		if x == prevX {
+			y--
+			reversed = append(reversed, edit{'+', b[y]})
+		} else {
+			x--
+			reversed = append(reversed, edit{'-', a[x]})
+		}
+	}
+	slices.Reverse(reversed)
+	return reversed
+}
+
+// nextChange returns the index of the first deletion or insertion at or
+// after start, or -1 if there is none.
+func nextChange(edits []edit, start int) int {
+	for i := start; i < len(edits); i++ {
+		if edits[i].kind != ' ' {
+			return i
+		}
+	}
+	return -1
+}
+
+// writeHunk writes edits[from:to] as a hunk with its @@ header.
+func writeHunk(out *strings.Builder, edits []edit, from, to int) {
+	aStart, bStart := 1, 1
+	for _, e := range edits[:from] {
+		if e.kind != '+' {
+			aStart++
+		}
+		if e.kind != '-' {
+			bStart++
+		}
+	}
+	aLen, bLen := 0, 0
+	for _, e := range edits[from:to] {
+		if e.kind != '+' {
+			aLen++
+		}
+		if e.kind != '-' {
+			bLen++
+		}
+	}
+	fmt.Fprintf(out, "@@ -%s +%s @@\n", hunkRange(aStart, aLen), hunkRange(bStart, bLen))
+	for _, e := range edits[from:to] {
+		out.WriteByte(e.kind)
+		out.WriteString(e.line)
+	}
+}
+
+// hunkRange formats the line range of a hunk side as GNU diff does.
+func hunkRange(start, length int) string {
+	switch length {
+	case 0:
+		return fmt.Sprintf("%d,0", start-1)
+	case 1:
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%d,%d", start, length)
+}
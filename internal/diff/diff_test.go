@@ -0,0 +1,85 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		expected string
+	}{
+		{
+			name: "equal",
+			a:    "a\nb\n",
+			b:    "a\nb\n",
+		},
+		{
+			name:     "changed line",
+			a:        "a\nb\nc\n",
+			b:        "a\nB\nc\n",
+			expected: "--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			name:     "new file",
+			a:        "",
+			b:        "a\n",
+			expected: "--- old\n+++ new\n@@ -0,0 +1 @@\n+a\n",
+		},
+		{
+			name:     "separate hunks",
+			a:        "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			b:        "0\n1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			expected: "--- old\n+++ new\n@@ -1,3 +1,4 @@\n+0\n 1\n 2\n 3\n@@ -7,4 +8,3 @@\n 7\n 8\n 9\n-10\n",
+		},
+		{
+			name:     "shared context",
+			a:        "1\n2\n3\n4\n5\n",
+			b:        "1\nx\n3\n4\ny\n",
+			expected: "--- old\n+++ new\n@@ -1,5 +1,5 @@\n 1\n-2\n+x\n 3\n 4\n-5\n+y\n",
+		},
+		{
+			name:     "missing newline",
+			a:        "a\nb",
+			b:        "a\nb\n",
+			expected: "--- old\n+++ new\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Unified("old", "new", tt.a, tt.b); got != tt.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestUnifiedLarge(t *testing.T) {
+	var a, b strings.Builder
+	for i := range 200000 {
+		fmt.Fprintf(&a, "line %d\n", i)
+		fmt.Fprintf(&b, "line %d\n", i)
+		if i == 100000 {
+			b.WriteString("-- comment\n")
+		}
+	}
+	expected := "--- old\n+++ new\n@@ -99999,6 +99999,7 @@\n line 99998\n line 99999\n line 100000\n+-- comment\n line 100001\n line 100002\n line 100003\n"
+	if got := Unified("old", "new", a.String(), b.String()); got != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, got)
+	}
+
+	// a and b differ in more lines than maxDistance
+	a.Reset()
+	b.Reset()
+	for i := range 200000 {
+		fmt.Fprintf(&a, "a %d\n", i)
+		fmt.Fprintf(&b, "b %d\n", i)
+	}
+	got := Unified("old", "new", a.String(), b.String())
+	if !strings.HasPrefix(got, "--- old\n+++ new\n@@ -1,200000 +1,200000 @@\n-a 0\n") || !strings.HasSuffix(got, "\n+b 199999\n") {
+		t.Errorf("expected all lines to be replaced, got %d bytes", len(got))
+	}
+}
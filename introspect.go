@@ -0,0 +1,41 @@
+package sqlgen
+
+import "context"
+
+// IntrospectOptions controls which part of a database is read.
+type IntrospectOptions struct {
+	// Schema is the schema (PostgreSQL) or database (MySQL) to read.
+	Schema string
+	// SkipTables lists the names of tables to leave out.
+	SkipTables []string
+}
+
+// Introspector reads a database schema into the schema model.
+type Introspector interface {
+	Introspect(ctx context.Context, opts IntrospectOptions) (*Schema, error)
+}
+
+// StaticIntrospector is an Introspector that returns a schema built elsewhere,
+// e.g. by hand in tests.
+type StaticIntrospector struct {
+	Schema *Schema
+}
+
+// Introspect returns a copy of the static schema without the skipped tables
+// and, if opts.Schema is set, the tables of other schemas. Tables without
+// schema, e.g. from MySQL DDL, belong to every schema.
+func (i StaticIntrospector) Introspect(ctx context.Context, opts IntrospectOptions) (*Schema, error) {
+	skip := make(map[string]bool, len(opts.SkipTables))
+	for _, name := range opts.SkipTables {
+		skip[name] = true
+	}
+
+	schema := &Schema{Name: i.Schema.Name}
+	for _, t := range i.Schema.Tables {
+		if skip[t.Name] || opts.Schema != "" && t.Schema != "" && t.Schema != opts.Schema {
+			continue
+		}
+		schema.Tables = append(schema.Tables, t)
+	}
+	return schema, nil
+}
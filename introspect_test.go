@@ -0,0 +1,41 @@
+package sqlgen
+
+import (
+	"context"
+	"testing"
+)
+
+func TestStaticIntrospector(t *testing.T) {
+	introspector := StaticIntrospector{Schema: &Schema{
+		Name:   "public",
+		Tables: []*Table{{Name: "users"}, {Name: "posts"}, {Name: "tags"}},
+	}}
+
+	schema, err := introspector.Introspect(context.Background(), IntrospectOptions{SkipTables: []string{"posts"}})
+	if err != nil {
+		t.Fatalf("Introspect failed: %v", err)
+	}
+	if schema.Name != "public" {
+		t.Errorf("expected schema name %q, got %q", "public", schema.Name)
+	}
+	if len(schema.Tables) != 2 || schema.Tables[0].Name != "users" || schema.Tables[1].Name != "tags" {
+		t.Errorf("unexpected tables: %v", schema.Tables)
+	}
+	if len(introspector.Schema.Tables) != 3 {
+		t.Errorf("Introspect must not modify the static schema")
+	}
+}
+
+func TestStaticIntrospectorSchema(t *testing.T) {
+	introspector := StaticIntrospector{Schema: &Schema{
+		Tables: []*Table{{Schema: "public", Name: "users"}, {Schema: "audit", Name: "logs"}, {Name: "tags"}},
+	}}
+
+	schema, err := introspector.Introspect(context.Background(), IntrospectOptions{Schema: "audit"})
+	if err != nil {
+		t.Fatalf("Introspect failed: %v", err)
+	}
+	if len(schema.Tables) != 2 || schema.Tables[0].Name != "logs" || schema.Tables[1].Name != "tags" {
+		t.Errorf("expected the tables of audit and without schema, got %v", schema.Tables)
+	}
+}
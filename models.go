@@ -0,0 +1,253 @@
+package sqlgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Model is the Go struct holding a row of a table.
+type Model struct {
+	Table *Table
+	// TableName is the name of Table in the queries, schema-qualified
+	// outside the default schema, e.g. "audit.logs".
+	TableName string
+	// Name is the struct name, the name of the table in query names,
+	// e.g. "User" for users.
+	Name   string
+	Fields []Field
+}
+
+// Field is a struct field of a Model.
+type Field struct {
+	Column *Column
+	// Name is the Go name of the column, e.g. "UserID" for user_id.
+	Name string
+	// Type is the Go type of the column, e.g. "int64" or "sql.NullString".
+	Type string
+}
+
+// goTypes maps the data types of PostgreSQL and MySQL to the Go types of
+// their non-null and nullable values. Types missing here map to
+// interface{}.
+var goTypes = map[string][2]string{
+	"boolean":                     {"bool", "sql.NullBool"},
+	"bool":                        {"bool", "sql.NullBool"},
+	"tinyint":                     {"int8", "sql.Null[int8]"},
+	"smallint":                    {"int16", "sql.NullInt16"},
+	"year":                        {"int16", "sql.NullInt16"},
+	"integer":                     {"int32", "sql.NullInt32"},
+	"int":                         {"int32", "sql.NullInt32"},
+	"mediumint":                   {"int32", "sql.NullInt32"},
+	"bigint":                      {"int64", "sql.NullInt64"},
+	"real":                        {"float32", "sql.Null[float32]"},
+	"float":                       {"float32", "sql.Null[float32]"},
+	"double precision":            {"float64", "sql.NullFloat64"},
+	"double":                      {"float64", "sql.NullFloat64"},
+	"numeric":                     {"string", "sql.NullString"},
+	"decimal":                     {"string", "sql.NullString"},
+	"money":                       {"string", "sql.NullString"},
+	"character varying":           {"string", "sql.NullString"},
+	"character":                   {"string", "sql.NullString"},
+	"varchar":                     {"string", "sql.NullString"},
+	"char":                        {"string", "sql.NullString"},
+	"text":                        {"string", "sql.NullString"},
+	"tinytext":                    {"string", "sql.NullString"},
+	"mediumtext":                  {"string", "sql.NullString"},
+	"longtext":                    {"string", "sql.NullString"},
+	"citext":                      {"string", "sql.NullString"},
+	"uuid":                        {"string", "sql.NullString"},
+	"inet":                        {"string", "sql.NullString"},
+	"cidr":                        {"string", "sql.NullString"},
+	"interval":                    {"string", "sql.NullString"},
+	"time without time zone":      {"string", "sql.NullString"},
+	"time with time zone":         {"string", "sql.NullString"},
+	"time":                        {"string", "sql.NullString"},
+	"enum":                        {"string", "sql.NullString"},
+	"set":                         {"string", "sql.NullString"},
+	"USER-DEFINED":                {"string", "sql.NullString"},
+	"timestamp without time zone": {"time.Time", "sql.NullTime"},
+	"timestamp with time zone":    {"time.Time", "sql.NullTime"},
+	"timestamp":                   {"time.Time", "sql.NullTime"},
+	"datetime":                    {"time.Time", "sql.NullTime"},
+	"date":                        {"time.Time", "sql.NullTime"},
+	"json":                        {"json.RawMessage", "json.RawMessage"},
+	"jsonb":                       {"json.RawMessage", "json.RawMessage"},
+	"bytea":                       {"[]byte", "[]byte"},
+	"binary":                      {"[]byte", "[]byte"},
+	"varbinary":                   {"[]byte", "[]byte"},
+	"bit":                         {"[]byte", "[]byte"},
+	"blob":                        {"[]byte", "[]byte"},
+	"tinyblob":                    {"[]byte", "[]byte"},
+	"mediumblob":                  {"[]byte", "[]byte"},
+	"longblob":                    {"[]byte", "[]byte"},
+}
+
+// goUnsignedTypes maps the integer types of MySQL to the Go types of their
+// UNSIGNED values, which overflow the signed ones.
+var goUnsignedTypes = map[string][2]string{
+	"tinyint":   {"uint8", "sql.Null[uint8]"},
+	"smallint":  {"uint16", "sql.Null[uint16]"},
+	"mediumint": {"uint32", "sql.Null[uint32]"},
+	"int":       {"uint32", "sql.Null[uint32]"},
+	"bigint":    {"uint64", "sql.Null[uint64]"},
+}
+
+// goPackages maps the package names in goTypes to their import paths.
+var goPackages = map[string]string{"sql": "database/sql", "json": "encoding/json", "time": "time"}
+
+// goType returns the Go type of the values of c. Nullable columns are
+// sql.Null types, or pointers with pointers set, except for the types
+// holding NULL themselves, e.g. []byte. UNSIGNED integers are unsigned in
+// Go too.
+func goType(c *Column, pointers bool) string {
+	types, ok := goTypes[c.DataType]
+	if unsigned, isInt := goUnsignedTypes[c.DataType]; c.Unsigned && isInt {
+		types = unsigned
+	}
+	if !ok {
+		return "interface{}"
+	}
+	if !c.Nullable || types[0] == types[1] {
+		return types[0]
+	}
+	if pointers {
+		return "*" + types[0]
+	}
+	return types[1]
+}
+
+// Models returns the Go struct of every table of the schema, with a field
+// for every selected column.
+func (g *Generator) Models(schema *Schema) []Model {
+	var models []Model
+	for _, t := range schema.Tables {
+		m := Model{Table: t, TableName: g.table(t), Name: goIdent(g.entityName(t))}
+		for _, c := range g.selectColumns(t) {
+			m.Fields = append(m.Fields, Field{Column: c, Name: GoFieldName(c.Name), Type: goType(c, g.NullPointers)})
+		}
+		models = append(models, m)
+	}
+	return models
+}
+
+// WriteModels writes a Go file of package pkg declaring the structs of
+// models. Every field has a db tag naming its column.
+func WriteModels(w io.Writer, pkg string, models []Model) error {
+	var imports []string
+	for _, m := range models {
+		for _, f := range m.Fields {
+			imports = addImport(imports, f.Type)
+		}
+	}
+
+	var b bytes.Buffer
+	writeGoHeader(&b, pkg, imports)
+	for _, m := range models {
+		fmt.Fprintf(&b, "\n// %s is a row of %s.\ntype %s struct {\n", m.Name, m.TableName, m.Name)
+		for _, f := range m.Fields {
+			fmt.Fprintf(&b, "\t%s %s `db:%q`\n", f.Name, f.Type, f.Column.Name)
+		}
+		b.WriteString("}\n")
+	}
+	return writeGoSource(w, b.Bytes())
+}
+
+// addImport adds the package of typ to imports unless it is builtin or
+// already imported.
+func addImport(imports []string, typ string) []string {
+	name, _, ok := strings.Cut(strings.TrimPrefix(typ, "*"), ".")
+	if p := goPackages[name]; ok && !slices.Contains(imports, p) {
+		return append(imports, p)
+	}
+	return imports
+}
+
+// writeGoHeader writes the generated code notice, the package clause and
+// the sorted imports of a Go file to b.
+func writeGoHeader(b *bytes.Buffer, pkg string, imports []string) {
+	imports = slices.Sorted(slices.Values(imports))
+	fmt.Fprintf(b, "// Code generated by sqlgen. DO NOT EDIT.\n\npackage %s\n", pkg)
+	if len(imports) == 1 {
+		fmt.Fprintf(b, "\nimport %q\n", imports[0])
+	} else if len(imports) > 1 {
+		b.WriteString("\nimport (\n")
+		for _, p := range imports {
+			fmt.Fprintf(b, "\t%q\n", p)
+		}
+		b.WriteString(")\n")
+	}
+}
+
+// writeGoSource writes src to w formatted by gofmt.
+func writeGoSource(w io.Writer, src []byte) error {
+	src, err := format.Source(src)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+// goInitialisms are the words written in upper case in Go names.
+var goInitialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true,
+	"DNS": true, "EOF": true, "GUID": true, "HTML": true, "HTTP": true,
+	"HTTPS": true, "ID": true, "IP": true, "JSON": true, "QPS": true,
+	"RAM": true, "RPC": true, "SLA": true, "SMTP": true, "SQL": true,
+	"SSH": true, "TCP": true, "TLS": true, "TTL": true, "UDP": true,
+	"UI": true, "UID": true, "UUID": true, "URI": true, "URL": true,
+	"UTF8": true, "VM": true, "XML": true, "XSRF": true, "XSS": true,
+}
+
+// GoFieldName converts a column name to an exported Go name, writing
+// initialisms in upper case, e.g. "UserID" for user_id.
+func GoFieldName(column string) string {
+	return goIdent(strings.Join(goWords(column), ""))
+}
+
+// goVarName converts a name to an unexported Go name, e.g. "userID" for
+// user_id.
+func goVarName(name string) string {
+	words := goWords(name)
+	if len(words) > 0 {
+		words[0] = strings.ToLower(words[0])
+	}
+	return goIdent(strings.Join(words, ""))
+}
+
+// goWords splits name at the characters other than letters and digits and
+// capitalizes the words, writing initialisms in upper case.
+func goWords(name string) []string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, word := range words {
+		if upper := strings.ToUpper(word); goInitialisms[upper] {
+			words[i] = upper
+		} else {
+			rs := []rune(word)
+			words[i] = string(unicode.ToUpper(rs[0])) + string(rs[1:])
+		}
+	}
+	return words
+}
+
+// goIdent returns name without the characters Go identifiers cannot hold,
+// prefixed with X when it would not start with a letter.
+func goIdent(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
+		}
+		return -1
+	}, name)
+	if r := []rune(name); len(r) == 0 || !unicode.IsLetter(r[0]) {
+		return "X" + name
+	}
+	return name
+}
@@ -0,0 +1,138 @@
+package sqlgen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGoFieldName(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"id", "ID"},
+		{"user_id", "UserID"},
+		{"api_key_url", "APIKeyURL"},
+		{"createdAt", "CreatedAt"},
+		{"display name", "DisplayName"},
+		{"2fa_secret", "X2faSecret"},
+	}
+
+	for _, tc := range testCases {
+		if result := GoFieldName(tc.input); result != tc.expected {
+			t.Errorf("GoFieldName(%q) = %q; want %q", tc.input, result, tc.expected)
+		}
+	}
+}
+
+func TestGoType(t *testing.T) {
+	testCases := []struct {
+		column   Column
+		pointers bool
+		expected string
+	}{
+		{Column{DataType: "int"}, false, "int32"},
+		{Column{DataType: "int", Unsigned: true}, false, "uint32"},
+		{Column{DataType: "tinyint", Unsigned: true, Nullable: true}, false, "sql.Null[uint8]"},
+		{Column{DataType: "bigint", Unsigned: true, Nullable: true}, true, "*uint64"},
+		{Column{DataType: "decimal", Unsigned: true}, false, "string"},
+		{Column{DataType: "text", Nullable: true}, false, "sql.NullString"},
+		{Column{DataType: "ARRAY"}, false, "interface{}"},
+	}
+
+	for _, tc := range testCases {
+		if result := goType(&tc.column, tc.pointers); result != tc.expected {
+			t.Errorf("goType(%+v, %v) = %q; want %q", tc.column, tc.pointers, result, tc.expected)
+		}
+	}
+}
+
+func TestWriteModels(t *testing.T) {
+	schema := &Schema{Tables: []*Table{
+		{
+			Name: "users",
+			Columns: []*Column{
+				{Name: "id", DataType: "bigint"},
+				{Name: "email", DataType: "character varying"},
+				{Name: "nickname", DataType: "text", Nullable: true},
+				{Name: "password_hash", DataType: "bytea"},
+				{Name: "settings", DataType: "jsonb", Nullable: true},
+				{Name: "created_at", DataType: "timestamp with time zone"},
+				{Name: "deleted_at", DataType: "timestamp with time zone", Nullable: true},
+			},
+		},
+		{
+			Schema: "audit",
+			Name:   "logs",
+			Columns: []*Column{
+				{Name: "id", DataType: "integer"},
+				{Name: "tags", DataType: "ARRAY", Nullable: true},
+			},
+		},
+	}}
+
+	tests := []struct {
+		name      string
+		generator Generator
+		expected  string
+	}{
+		{
+			name: "sql.Null types",
+			generator: Generator{
+				Dialect:       PostgresDialect{},
+				DefaultSchema: "public",
+				Tables:        map[string]TableOptions{"users": {HiddenColumns: []string{"password_hash"}}},
+			},
+			expected: "// Code generated by sqlgen. DO NOT EDIT.\n\n" +
+				"package models\n\n" +
+				"import (\n\t\"database/sql\"\n\t\"encoding/json\"\n\t\"time\"\n)\n\n" +
+				"// User is a row of users.\n" +
+				"type User struct {\n" +
+				"\tID        int64           `db:\"id\"`\n" +
+				"\tEmail     string          `db:\"email\"`\n" +
+				"\tNickname  sql.NullString  `db:\"nickname\"`\n" +
+				"\tSettings  json.RawMessage `db:\"settings\"`\n" +
+				"\tCreatedAt time.Time       `db:\"created_at\"`\n" +
+				"\tDeletedAt sql.NullTime    `db:\"deleted_at\"`\n" +
+				"}\n\n" +
+				"// AuditLog is a row of audit.logs.\n" +
+				"type AuditLog struct {\n" +
+				"\tID   int32       `db:\"id\"`\n" +
+				"\tTags interface{} `db:\"tags\"`\n" +
+				"}\n",
+		},
+		{
+			name:      "pointers",
+			generator: Generator{Dialect: PostgresDialect{}, NullPointers: true},
+			expected: "// Code generated by sqlgen. DO NOT EDIT.\n\n" +
+				"package models\n\n" +
+				"import (\n\t\"encoding/json\"\n\t\"time\"\n)\n\n" +
+				"// User is a row of users.\n" +
+				"type User struct {\n" +
+				"\tID           int64           `db:\"id\"`\n" +
+				"\tEmail        string          `db:\"email\"`\n" +
+				"\tNickname     *string         `db:\"nickname\"`\n" +
+				"\tPasswordHash []byte          `db:\"password_hash\"`\n" +
+				"\tSettings     json.RawMessage `db:\"settings\"`\n" +
+				"\tCreatedAt    time.Time       `db:\"created_at\"`\n" +
+				"\tDeletedAt    *time.Time      `db:\"deleted_at\"`\n" +
+				"}\n\n" +
+				"// Log is a row of logs.\n" +
+				"type Log struct {\n" +
+				"\tID   int32       `db:\"id\"`\n" +
+				"\tTags interface{} `db:\"tags\"`\n" +
+				"}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			if err := WriteModels(&buf, "models", tt.generator.Models(schema)); err != nil {
+				t.Fatalf("WriteModels failed: %v", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.expected, buf.String())
+			}
+		})
+	}
+}
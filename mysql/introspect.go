@@ -0,0 +1,271 @@
+// Package mysql reads MySQL schemas through INFORMATION_SCHEMA.
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+
+	driver "github.com/go-sql-driver/mysql"
+	"github.com/miyataka/sqlgen"
+)
+
+// Introspector reads schemas from a database connection.
+type Introspector struct {
+	db *sql.DB
+}
+
+// NewIntrospector returns an Introspector reading through db.
+func NewIntrospector(db *sql.DB) *Introspector {
+	return &Introspector{db: db}
+}
+
+const introspectMysqlColumns = `
+SELECT
+    c.TABLE_NAME,
+    t.TABLE_TYPE = 'VIEW' AS is_view,
+    c.COLUMN_NAME,
+    c.ORDINAL_POSITION,
+    c.DATA_TYPE,
+    c.IS_NULLABLE = 'YES' AS nullable,
+    c.COLUMN_DEFAULT,
+    c.EXTRA LIKE '%%auto_increment%%' AS auto_increment,
+    c.EXTRA LIKE '%%VIRTUAL GENERATED%%' OR c.EXTRA LIKE '%%STORED GENERATED%%' AS is_generated,
+    c.COLUMN_TYPE LIKE '%%unsigned%%' AS is_unsigned
+FROM
+    INFORMATION_SCHEMA.COLUMNS c
+    JOIN INFORMATION_SCHEMA.TABLES t
+    ON t.TABLE_SCHEMA = c.TABLE_SCHEMA
+    AND t.TABLE_NAME = c.TABLE_NAME
+WHERE
+    c.TABLE_SCHEMA = ? -- schema/database name
+    %s
+ORDER BY
+    c.TABLE_NAME, c.ORDINAL_POSITION;
+`
+
+const introspectMysqlPrimaryKeys = `
+SELECT
+    kcu.TABLE_NAME,
+    kcu.CONSTRAINT_NAME,
+    kcu.COLUMN_NAME
+FROM
+    INFORMATION_SCHEMA.KEY_COLUMN_USAGE kcu
+WHERE
+    kcu.TABLE_SCHEMA = ? -- schema/database name
+    AND kcu.CONSTRAINT_NAME = 'PRIMARY'
+ORDER BY
+    kcu.TABLE_NAME, kcu.ORDINAL_POSITION;
+`
+
+const introspectMysqlIndexes = `
+SELECT
+    s.TABLE_NAME,
+    s.INDEX_NAME,
+    s.NON_UNIQUE = 0 AS is_unique,
+    s.COLUMN_NAME
+FROM
+    INFORMATION_SCHEMA.STATISTICS s
+WHERE
+    s.TABLE_SCHEMA = ? -- schema/database name
+    AND s.INDEX_NAME <> 'PRIMARY'
+ORDER BY
+    s.TABLE_NAME, s.INDEX_NAME, s.SEQ_IN_INDEX;
+`
+
+const introspectMysqlForeignKeys = `
+SELECT
+    kcu.TABLE_NAME,
+    kcu.CONSTRAINT_NAME,
+    kcu.COLUMN_NAME,
+    kcu.REFERENCED_TABLE_SCHEMA,
+    kcu.REFERENCED_TABLE_NAME,
+    kcu.REFERENCED_COLUMN_NAME
+FROM
+    INFORMATION_SCHEMA.REFERENTIAL_CONSTRAINTS rc
+    JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE kcu
+    ON rc.CONSTRAINT_SCHEMA = kcu.CONSTRAINT_SCHEMA
+    AND rc.TABLE_NAME = kcu.TABLE_NAME
+    AND rc.CONSTRAINT_NAME = kcu.CONSTRAINT_NAME
+WHERE
+    rc.CONSTRAINT_SCHEMA = ? -- schema/database name
+ORDER BY
+    kcu.TABLE_NAME, kcu.CONSTRAINT_NAME, kcu.ORDINAL_POSITION;
+`
+
+// buildSkipTablesCondition returns the condition excluding skipTables.
+func buildSkipTablesCondition(skipTables []string) string {
+	if len(skipTables) == 0 {
+		return ""
+	}
+	placeholders := make([]string, len(skipTables))
+	for i := range skipTables {
+		placeholders[i] = "?"
+	}
+	return fmt.Sprintf("AND c.TABLE_NAME NOT IN (%s)", strings.Join(placeholders, ", "))
+}
+
+// Introspect reads the tables of the database opts.Schema into the schema model.
+func (i *Introspector) Introspect(ctx context.Context, opts sqlgen.IntrospectOptions) (*sqlgen.Schema, error) {
+	schema := &sqlgen.Schema{Name: opts.Schema}
+	if err := i.readColumns(ctx, opts, schema); err != nil {
+		return nil, err
+	}
+	if err := i.readPrimaryKeys(ctx, schema); err != nil {
+		return nil, err
+	}
+	if err := i.readIndexes(ctx, schema); err != nil {
+		return nil, err
+	}
+	if err := i.readForeignKeys(ctx, schema); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// readColumns adds the tables and their columns to schema.
+func (i *Introspector) readColumns(ctx context.Context, opts sqlgen.IntrospectOptions, schema *sqlgen.Schema) error {
+	query := fmt.Sprintf(introspectMysqlColumns, buildSkipTablesCondition(opts.SkipTables))
+	args := []interface{}{opts.Schema}
+	for _, t := range opts.SkipTables {
+		args = append(args, t)
+	}
+
+	rows, err := i.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableName string
+		var isView bool
+		var col sqlgen.Column
+		var def sql.NullString
+		if err := rows.Scan(&tableName, &isView, &col.Name, &col.OrdinalPosition, &col.DataType, &col.Nullable, &def, &col.AutoIncrement, &col.Generated, &col.Unsigned); err != nil {
+			return err
+		}
+		if def.Valid {
+			col.Default = &def.String
+		}
+		table := schema.Table(tableName)
+		if table == nil {
+			table = &sqlgen.Table{Schema: opts.Schema, Name: tableName}
+			if isView {
+				table.Kind = sqlgen.KindView
+			}
+			schema.Tables = append(schema.Tables, table)
+		}
+		table.Columns = append(table.Columns, &col)
+	}
+	return rows.Err()
+}
+
+// readPrimaryKeys adds the primary keys to the tables of schema.
+func (i *Introspector) readPrimaryKeys(ctx context.Context, schema *sqlgen.Schema) error {
+	rows, err := i.db.QueryContext(ctx, introspectMysqlPrimaryKeys, schema.Name)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableName, constraintName, columnName string
+		if err := rows.Scan(&tableName, &constraintName, &columnName); err != nil {
+			return err
+		}
+		table := schema.Table(tableName)
+		if table == nil {
+			continue
+		}
+		if table.PrimaryKey == nil {
+			table.PrimaryKey = &sqlgen.PrimaryKey{Name: constraintName}
+		}
+		table.PrimaryKey.Columns = append(table.PrimaryKey.Columns, columnName)
+	}
+	return rows.Err()
+}
+
+// readIndexes adds the indexes and unique constraints other than the primary
+// key to the tables of schema. Functional indexes are left out.
+func (i *Introspector) readIndexes(ctx context.Context, schema *sqlgen.Schema) error {
+	rows, err := i.db.QueryContext(ctx, introspectMysqlIndexes, schema.Name)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	functional := make(map[*sqlgen.Index]bool)
+	for rows.Next() {
+		var tableName, indexName string
+		var unique bool
+		var columnName sql.NullString
+		if err := rows.Scan(&tableName, &indexName, &unique, &columnName); err != nil {
+			return err
+		}
+		table := schema.Table(tableName)
+		if table == nil {
+			continue
+		}
+		index := table.Index(indexName)
+		if index == nil {
+			index = &sqlgen.Index{Name: indexName, Unique: unique}
+			table.Indexes = append(table.Indexes, index)
+		}
+		if !columnName.Valid {
+			// key part is an expression
+			functional[index] = true
+			continue
+		}
+		index.Columns = append(index.Columns, columnName.String)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, table := range schema.Tables {
+		table.Indexes = slices.DeleteFunc(table.Indexes, func(index *sqlgen.Index) bool {
+			return functional[index]
+		})
+	}
+	return nil
+}
+
+// readForeignKeys adds the foreign keys to the tables of schema.
+func (i *Introspector) readForeignKeys(ctx context.Context, schema *sqlgen.Schema) error {
+	rows, err := i.db.QueryContext(ctx, introspectMysqlForeignKeys, schema.Name)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableName, constraintName, columnName, refSchema, refTable, refColumn string
+		if err := rows.Scan(&tableName, &constraintName, &columnName, &refSchema, &refTable, &refColumn); err != nil {
+			return err
+		}
+		table := schema.Table(tableName)
+		if table == nil {
+			continue
+		}
+		fk := table.ForeignKey(constraintName)
+		if fk == nil {
+			fk = &sqlgen.ForeignKey{Name: constraintName, ReferencedSchema: refSchema, ReferencedTable: refTable}
+			table.ForeignKeys = append(table.ForeignKeys, fk)
+		}
+		fk.Columns = append(fk.Columns, columnName)
+		fk.ReferencedColumns = append(fk.ReferencedColumns, refColumn)
+	}
+	return rows.Err()
+}
+
+// DatabaseFromDSN parses a DSN string and returns the database name.
+func DatabaseFromDSN(dsn string) (string, error) {
+	cfg, err := driver.ParseDSN(dsn)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse DSN: %w", err)
+	}
+	return cfg.DBName, nil
+}
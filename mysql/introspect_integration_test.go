@@ -0,0 +1,158 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/miyataka/sqlgen"
+	tcmysql "github.com/testcontainers/testcontainers-go/modules/mysql"
+)
+
+func setupMySQL(t *testing.T, ddl string) *sql.DB {
+	t.Helper()
+	ctx := context.Background()
+
+	mysqlContainer, err := tcmysql.Run(ctx,
+		"mysql:8",
+		tcmysql.WithDatabase("testdb"),
+		tcmysql.WithUsername("root"),
+		tcmysql.WithPassword("password"),
+	)
+	if err != nil {
+		t.Fatalf("failed to start container: %s", err)
+	}
+	t.Cleanup(func() {
+		if err := mysqlContainer.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	})
+
+	connectionString, err := mysqlContainer.ConnectionString(ctx)
+	if err != nil {
+		t.Fatalf("failed to get connection string: %s", err)
+	}
+
+	db, err := sql.Open("mysql", connectionString)
+	if err != nil {
+		t.Fatalf("failed to connect to database: %s", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	// MySQL requires executing statements one at a time
+	for _, stmt := range strings.Split(ddl, ";") {
+		stmt = strings.TrimSpace(stmt)
+		if stmt == "" {
+			continue
+		}
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("failed to create tables: %s", err)
+		}
+	}
+	return db
+}
+
+func TestIntrospectIntegration(t *testing.T) {
+	db := setupMySQL(t, `
+	CREATE TABLE users (
+		id INT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+		name VARCHAR(100) NOT NULL,
+		email VARCHAR(100) UNIQUE
+	);
+
+	CREATE TABLE order_items (
+		order_id INT,
+		item_id INT,
+		quantity INT NOT NULL DEFAULT 1,
+		doubled INT AS (quantity * 2),
+		tripled INT AS (quantity * 3) STORED,
+		PRIMARY KEY (order_id, item_id)
+	);
+
+	CREATE TABLE shipments (
+		id INT PRIMARY KEY,
+		order_id INT NOT NULL,
+		item_id INT NOT NULL,
+		CONSTRAINT shipments_order_item_fkey FOREIGN KEY (order_id, item_id) REFERENCES order_items (order_id, item_id)
+	);
+
+	CREATE INDEX order_items_item_id_idx ON order_items (item_id);
+	CREATE UNIQUE INDEX order_items_item_id_quantity_key ON order_items (item_id, quantity);
+	CREATE UNIQUE INDEX users_lower_name_key ON users ((lower(name)));
+
+	CREATE VIEW user_names AS SELECT id, name FROM users;
+	`)
+
+	schema, err := NewIntrospector(db).Introspect(context.Background(), sqlgen.IntrospectOptions{Schema: "testdb"})
+	if err != nil {
+		t.Fatalf("failed to introspect: %s", err)
+	}
+
+	users := schema.Table("users")
+	if users == nil {
+		t.Fatal("missing users table")
+	}
+	if got := sqlgen.ColumnNames(users.Columns); !reflect.DeepEqual(got, []string{"id", "name", "email"}) {
+		t.Errorf("unexpected users columns: %v", got)
+	}
+	if !users.Column("id").AutoIncrement {
+		t.Error("users.id should be auto increment")
+	}
+	if !users.Column("id").Unsigned {
+		t.Error("users.id should be unsigned")
+	}
+	if users.Kind != sqlgen.KindBaseTable {
+		t.Errorf("unexpected kind of users: %q", users.Kind)
+	}
+	if view := schema.Table("user_names"); view == nil || view.Kind != sqlgen.KindView {
+		t.Errorf("expected user_names to be a view: %+v", view)
+	}
+	if users.Column("name").Nullable || !users.Column("email").Nullable {
+		t.Error("unexpected nullability of users columns")
+	}
+
+	orderItems := schema.Table("order_items")
+	if orderItems == nil {
+		t.Fatal("missing order_items table")
+	}
+	if orderItems.PrimaryKey == nil || !reflect.DeepEqual(orderItems.PrimaryKey.Columns, []string{"order_id", "item_id"}) {
+		t.Errorf("unexpected order_items primary key: %v", orderItems.PrimaryKey)
+	}
+	if def := orderItems.Column("quantity").Default; def == nil || *def != "1" {
+		t.Errorf("unexpected default of order_items.quantity: %v", def)
+	}
+	if orderItems.Column("quantity").Unsigned {
+		t.Error("order_items.quantity should be signed")
+	}
+	if orderItems.Column("quantity").Generated || !orderItems.Column("doubled").Generated || !orderItems.Column("tripled").Generated {
+		t.Error("unexpected generated columns of order_items")
+	}
+
+	if len(users.Indexes) != 1 || !users.Indexes[0].Unique || !reflect.DeepEqual(users.Indexes[0].Columns, []string{"email"}) {
+		t.Errorf("unexpected users indexes: %v", users.Indexes)
+	}
+	unique := orderItems.Index("order_items_item_id_quantity_key")
+	if unique == nil || !unique.Unique || !reflect.DeepEqual(unique.Columns, []string{"item_id", "quantity"}) {
+		t.Errorf("unexpected unique index: %v", unique)
+	}
+	if index := orderItems.Index("order_items_item_id_idx"); index == nil || index.Unique {
+		t.Errorf("unexpected non-unique index: %v", index)
+	}
+
+	shipments := schema.Table("shipments")
+	if shipments == nil {
+		t.Fatal("missing shipments table")
+	}
+	fk := shipments.ForeignKey("shipments_order_item_fkey")
+	if fk == nil {
+		t.Fatal("missing shipments foreign key")
+	}
+	if fk.ReferencedTable != "order_items" ||
+		!reflect.DeepEqual(fk.Columns, []string{"order_id", "item_id"}) ||
+		!reflect.DeepEqual(fk.ReferencedColumns, []string{"order_id", "item_id"}) {
+		t.Errorf("unexpected foreign key: %+v", fk)
+	}
+}
@@ -0,0 +1,36 @@
+package mysql
+
+import "testing"
+
+func TestBuildSkipTablesCondition(t *testing.T) {
+	tests := []struct {
+		name       string
+		skipTables []string
+		expected   string
+	}{
+		{
+			name:       "empty list",
+			skipTables: []string{},
+			expected:   "",
+		},
+		{
+			name:       "single table",
+			skipTables: []string{"users"},
+			expected:   "AND c.TABLE_NAME NOT IN (?)",
+		},
+		{
+			name:       "multiple tables",
+			skipTables: []string{"users", "posts", "comments"},
+			expected:   "AND c.TABLE_NAME NOT IN (?, ?, ?)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := buildSkipTablesCondition(tt.skipTables)
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
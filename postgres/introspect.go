@@ -0,0 +1,320 @@
+// Package postgres reads PostgreSQL schemas through information_schema.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/miyataka/sqlgen"
+)
+
+// Introspector reads schemas from a database connection.
+type Introspector struct {
+	db *sql.DB
+}
+
+// NewIntrospector returns an Introspector reading through db.
+func NewIntrospector(db *sql.DB) *Introspector {
+	return &Introspector{db: db}
+}
+
+// introspectPostgresColumns reads the columns of tables, views and foreign
+// tables from information_schema and the ones of materialized views, which it
+// leaves out, from pg_catalog. Partitions are left out, as they are read
+// through their partitioned table.
+const introspectPostgresColumns = `
+SELECT
+    c.table_name,
+    c.relkind,
+    c.column_name,
+    c.ordinal_position,
+    c.data_type,
+    c.nullable,
+    c.column_default,
+    c.identity_generation,
+    c.generated
+FROM (
+    SELECT
+        ic.table_name::text,
+        cl.relkind::text,
+        ic.column_name::text,
+        ic.ordinal_position::int,
+        ic.data_type::text,
+        ic.is_nullable = 'YES' AS nullable,
+        ic.column_default::text,
+        ic.identity_generation::text,
+        ic.is_generated = 'ALWAYS' AS generated
+    FROM
+        information_schema.columns ic
+        JOIN pg_catalog.pg_namespace n ON n.nspname = ic.table_schema
+        JOIN pg_catalog.pg_class cl ON cl.relnamespace = n.oid AND cl.relname = ic.table_name
+    WHERE
+        ic.table_schema = $1 -- schema name
+        AND NOT cl.relispartition
+    UNION ALL
+    SELECT
+        cl.relname::text,
+        cl.relkind::text,
+        a.attname::text,
+        a.attnum::int,
+        CASE
+            WHEN t.typcategory = 'A' THEN 'ARRAY'
+            WHEN t.typtype IN ('e', 'c', 'r', 'm') THEN 'USER-DEFINED'
+            ELSE pg_catalog.format_type(a.atttypid, NULL)
+        END,
+        NOT a.attnotnull,
+        NULL,
+        NULL,
+        FALSE
+    FROM
+        pg_catalog.pg_attribute a
+        JOIN pg_catalog.pg_class cl ON cl.oid = a.attrelid
+        JOIN pg_catalog.pg_namespace n ON n.oid = cl.relnamespace
+        JOIN pg_catalog.pg_type t ON t.oid = a.atttypid
+    WHERE
+        n.nspname = $1 -- schema name
+        AND cl.relkind = 'm'
+        AND a.attnum > 0
+        AND NOT a.attisdropped
+) c
+WHERE
+    TRUE
+    %s
+ORDER BY
+    c.table_name, c.ordinal_position;
+`
+
+const introspectPostgresPrimaryKeys = `
+SELECT
+    kcu.table_name,
+    tc.constraint_name,
+    kcu.column_name
+FROM
+    information_schema.table_constraints tc
+    JOIN information_schema.key_column_usage kcu
+    ON tc.constraint_name = kcu.constraint_name
+    AND tc.table_schema = kcu.table_schema
+    AND tc.table_name = kcu.table_name
+WHERE
+    tc.constraint_type = 'PRIMARY KEY'
+    AND kcu.table_schema = $1 -- schema name
+ORDER BY
+    kcu.table_name, kcu.ordinal_position;
+`
+
+const introspectPostgresIndexes = `
+SELECT
+    t.relname AS table_name,
+    i.relname AS index_name,
+    ix.indisunique AS is_unique,
+    a.attname AS column_name
+FROM
+    pg_catalog.pg_index ix
+    JOIN pg_catalog.pg_class t ON t.oid = ix.indrelid
+    JOIN pg_catalog.pg_class i ON i.oid = ix.indexrelid
+    JOIN pg_catalog.pg_namespace n ON n.oid = t.relnamespace
+    CROSS JOIN LATERAL unnest(ix.indkey::int2[]) WITH ORDINALITY AS k(attnum, ord)
+    JOIN pg_catalog.pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+WHERE
+    n.nspname = $1 -- schema name
+    AND k.ord <= ix.indnkeyatts -- skip INCLUDE columns
+    AND NOT ix.indisprimary
+    AND ix.indpred IS NULL -- skip partial indexes
+    AND 0 <> ALL (ix.indkey::int2[]) -- skip expression indexes
+ORDER BY
+    t.relname, i.relname, k.ord;
+`
+
+const introspectPostgresForeignKeys = `
+SELECT
+    t.relname AS table_name,
+    c.conname AS constraint_name,
+    a.attname AS column_name,
+    rn.nspname AS referenced_schema,
+    rt.relname AS referenced_table,
+    ra.attname AS referenced_column
+FROM
+    pg_catalog.pg_constraint c
+    JOIN pg_catalog.pg_class t ON t.oid = c.conrelid
+    JOIN pg_catalog.pg_namespace n ON n.oid = t.relnamespace
+    JOIN pg_catalog.pg_class rt ON rt.oid = c.confrelid
+    JOIN pg_catalog.pg_namespace rn ON rn.oid = rt.relnamespace
+    CROSS JOIN LATERAL unnest(c.conkey, c.confkey) WITH ORDINALITY AS k(attnum, refattnum, ord)
+    JOIN pg_catalog.pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = k.attnum
+    JOIN pg_catalog.pg_attribute ra ON ra.attrelid = c.confrelid AND ra.attnum = k.refattnum
+WHERE
+    c.contype = 'f'
+    AND n.nspname = $1 -- schema name
+ORDER BY
+    t.relname, c.conname, k.ord;
+`
+
+// buildSkipTablesCondition returns the condition excluding skipTables,
+// numbering the placeholders from baseIndex.
+func buildSkipTablesCondition(skipTables []string, baseIndex int) string {
+	if len(skipTables) == 0 {
+		return ""
+	}
+	placeholders := make([]string, len(skipTables))
+	for i := range skipTables {
+		placeholders[i] = fmt.Sprintf("$%d", baseIndex+i)
+	}
+	return fmt.Sprintf("AND c.table_name NOT IN (%s)", strings.Join(placeholders, ", "))
+}
+
+// Introspect reads the tables of opts.Schema into the schema model.
+func (i *Introspector) Introspect(ctx context.Context, opts sqlgen.IntrospectOptions) (*sqlgen.Schema, error) {
+	schema := &sqlgen.Schema{Name: opts.Schema}
+	if err := i.readColumns(ctx, opts, schema); err != nil {
+		return nil, err
+	}
+	if err := i.readPrimaryKeys(ctx, schema); err != nil {
+		return nil, err
+	}
+	if err := i.readIndexes(ctx, schema); err != nil {
+		return nil, err
+	}
+	if err := i.readForeignKeys(ctx, schema); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// tableKinds maps pg_class.relkind to the kinds of tables.
+var tableKinds = map[string]sqlgen.TableKind{
+	"r": sqlgen.KindBaseTable,
+	"v": sqlgen.KindView,
+	"m": sqlgen.KindMaterializedView,
+	"p": sqlgen.KindPartitionedTable,
+	"f": sqlgen.KindForeignTable,
+}
+
+// identities maps information_schema.columns.identity_generation to the
+// kinds of identity columns.
+var identities = map[string]sqlgen.Identity{
+	"ALWAYS":     sqlgen.IdentityAlways,
+	"BY DEFAULT": sqlgen.IdentityByDefault,
+}
+
+// readColumns adds the tables and their columns to schema.
+func (i *Introspector) readColumns(ctx context.Context, opts sqlgen.IntrospectOptions, schema *sqlgen.Schema) error {
+	query := fmt.Sprintf(introspectPostgresColumns, buildSkipTablesCondition(opts.SkipTables, 2))
+	args := []interface{}{opts.Schema}
+	for _, t := range opts.SkipTables {
+		args = append(args, t)
+	}
+
+	rows, err := i.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableName, relkind string
+		var col sqlgen.Column
+		var def, identity sql.NullString
+		if err := rows.Scan(&tableName, &relkind, &col.Name, &col.OrdinalPosition, &col.DataType, &col.Nullable, &def, &identity, &col.Generated); err != nil {
+			return err
+		}
+		if def.Valid {
+			col.Default = &def.String
+			col.AutoIncrement = strings.HasPrefix(def.String, "nextval(")
+		}
+		if identity.Valid {
+			col.Identity = identities[identity.String]
+			col.AutoIncrement = true
+		}
+		table := schema.Table(tableName)
+		if table == nil {
+			table = &sqlgen.Table{Schema: opts.Schema, Name: tableName, Kind: tableKinds[relkind]}
+			schema.Tables = append(schema.Tables, table)
+		}
+		table.Columns = append(table.Columns, &col)
+	}
+	return rows.Err()
+}
+
+// readPrimaryKeys adds the primary keys to the tables of schema.
+func (i *Introspector) readPrimaryKeys(ctx context.Context, schema *sqlgen.Schema) error {
+	rows, err := i.db.QueryContext(ctx, introspectPostgresPrimaryKeys, schema.Name)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableName, constraintName, columnName string
+		if err := rows.Scan(&tableName, &constraintName, &columnName); err != nil {
+			return err
+		}
+		table := schema.Table(tableName)
+		if table == nil {
+			continue
+		}
+		if table.PrimaryKey == nil {
+			table.PrimaryKey = &sqlgen.PrimaryKey{Name: constraintName}
+		}
+		table.PrimaryKey.Columns = append(table.PrimaryKey.Columns, columnName)
+	}
+	return rows.Err()
+}
+
+// readIndexes adds the indexes and unique constraints other than the primary
+// key to the tables of schema.
+func (i *Introspector) readIndexes(ctx context.Context, schema *sqlgen.Schema) error {
+	rows, err := i.db.QueryContext(ctx, introspectPostgresIndexes, schema.Name)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableName, indexName, columnName string
+		var unique bool
+		if err := rows.Scan(&tableName, &indexName, &unique, &columnName); err != nil {
+			return err
+		}
+		table := schema.Table(tableName)
+		if table == nil {
+			continue
+		}
+		index := table.Index(indexName)
+		if index == nil {
+			index = &sqlgen.Index{Name: indexName, Unique: unique}
+			table.Indexes = append(table.Indexes, index)
+		}
+		index.Columns = append(index.Columns, columnName)
+	}
+	return rows.Err()
+}
+
+// readForeignKeys adds the foreign keys to the tables of schema.
+func (i *Introspector) readForeignKeys(ctx context.Context, schema *sqlgen.Schema) error {
+	rows, err := i.db.QueryContext(ctx, introspectPostgresForeignKeys, schema.Name)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableName, constraintName, columnName, refSchema, refTable, refColumn string
+		if err := rows.Scan(&tableName, &constraintName, &columnName, &refSchema, &refTable, &refColumn); err != nil {
+			return err
+		}
+		table := schema.Table(tableName)
+		if table == nil {
+			continue
+		}
+		fk := table.ForeignKey(constraintName)
+		if fk == nil {
+			fk = &sqlgen.ForeignKey{Name: constraintName, ReferencedSchema: refSchema, ReferencedTable: refTable}
+			table.ForeignKeys = append(table.ForeignKeys, fk)
+		}
+		fk.Columns = append(fk.Columns, columnName)
+		fk.ReferencedColumns = append(fk.ReferencedColumns, refColumn)
+	}
+	return rows.Err()
+}
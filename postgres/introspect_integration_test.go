@@ -0,0 +1,210 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/miyataka/sqlgen"
+	"github.com/testcontainers/testcontainers-go"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func setupPostgres(t *testing.T, ddl string) *sql.DB {
+	t.Helper()
+	ctx := context.Background()
+
+	postgresContainer, err := tcpostgres.Run(ctx,
+		"postgres:16-alpine",
+		tcpostgres.WithDatabase("testdb"),
+		tcpostgres.WithUsername("postgres"),
+		tcpostgres.WithPassword("postgres"),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2)),
+	)
+	if err != nil {
+		t.Fatalf("failed to start container: %s", err)
+	}
+	t.Cleanup(func() {
+		if err := postgresContainer.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	})
+
+	connectionString, err := postgresContainer.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		t.Fatalf("failed to get connection string: %s", err)
+	}
+
+	db, err := sql.Open("pgx", connectionString)
+	if err != nil {
+		t.Fatalf("failed to connect to database: %s", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(ddl); err != nil {
+		t.Fatalf("failed to create tables: %s", err)
+	}
+	return db
+}
+
+func TestIntrospectIntegration(t *testing.T) {
+	db := setupPostgres(t, `
+	CREATE TABLE users (
+		id SERIAL PRIMARY KEY,
+		name VARCHAR(100) NOT NULL,
+		email VARCHAR(100) UNIQUE
+	);
+
+	CREATE TABLE order_items (
+		order_id INTEGER,
+		item_id INTEGER,
+		quantity INTEGER NOT NULL DEFAULT 1,
+		PRIMARY KEY (order_id, item_id)
+	);
+
+	CREATE TABLE shipments (
+		id INTEGER PRIMARY KEY,
+		order_id INTEGER NOT NULL,
+		item_id INTEGER NOT NULL,
+		CONSTRAINT shipments_order_item_fkey FOREIGN KEY (order_id, item_id) REFERENCES order_items (order_id, item_id)
+	);
+
+	CREATE INDEX order_items_item_id_idx ON order_items (item_id);
+	CREATE UNIQUE INDEX order_items_item_id_quantity_key ON order_items (item_id, quantity);
+	CREATE UNIQUE INDEX users_lower_name_key ON users (lower(name));
+	CREATE UNIQUE INDEX shipments_order_id_key ON shipments (order_id) INCLUDE (item_id);
+	`)
+
+	schema, err := NewIntrospector(db).Introspect(context.Background(), sqlgen.IntrospectOptions{Schema: "public"})
+	if err != nil {
+		t.Fatalf("failed to introspect: %s", err)
+	}
+
+	users := schema.Table("users")
+	if users == nil {
+		t.Fatal("missing users table")
+	}
+	if got := sqlgen.ColumnNames(users.Columns); !reflect.DeepEqual(got, []string{"id", "name", "email"}) {
+		t.Errorf("unexpected users columns: %v", got)
+	}
+	if !users.Column("id").AutoIncrement {
+		t.Error("users.id should be auto increment")
+	}
+	if users.Column("name").Nullable || !users.Column("email").Nullable {
+		t.Error("unexpected nullability of users columns")
+	}
+
+	orderItems := schema.Table("order_items")
+	if orderItems == nil {
+		t.Fatal("missing order_items table")
+	}
+	if orderItems.PrimaryKey == nil || !reflect.DeepEqual(orderItems.PrimaryKey.Columns, []string{"order_id", "item_id"}) {
+		t.Errorf("unexpected order_items primary key: %v", orderItems.PrimaryKey)
+	}
+	if def := orderItems.Column("quantity").Default; def == nil || *def != "1" {
+		t.Errorf("unexpected default of order_items.quantity: %v", def)
+	}
+
+	if len(users.Indexes) != 1 || !users.Indexes[0].Unique || !reflect.DeepEqual(users.Indexes[0].Columns, []string{"email"}) {
+		t.Errorf("unexpected users indexes: %v", users.Indexes)
+	}
+	unique := orderItems.Index("order_items_item_id_quantity_key")
+	if unique == nil || !unique.Unique || !reflect.DeepEqual(unique.Columns, []string{"item_id", "quantity"}) {
+		t.Errorf("unexpected unique index: %v", unique)
+	}
+	if index := orderItems.Index("order_items_item_id_idx"); index == nil || index.Unique {
+		t.Errorf("unexpected non-unique index: %v", index)
+	}
+
+	shipments := schema.Table("shipments")
+	if shipments == nil {
+		t.Fatal("missing shipments table")
+	}
+	if covering := shipments.Index("shipments_order_id_key"); covering == nil || !reflect.DeepEqual(covering.Columns, []string{"order_id"}) {
+		t.Errorf("unexpected covering index: %v", covering)
+	}
+	fk := shipments.ForeignKey("shipments_order_item_fkey")
+	if fk == nil {
+		t.Fatal("missing shipments foreign key")
+	}
+	if fk.ReferencedTable != "order_items" ||
+		!reflect.DeepEqual(fk.Columns, []string{"order_id", "item_id"}) ||
+		!reflect.DeepEqual(fk.ReferencedColumns, []string{"order_id", "item_id"}) {
+		t.Errorf("unexpected foreign key: %+v", fk)
+	}
+}
+
+func TestIntrospectTableKindsIntegration(t *testing.T) {
+	db := setupPostgres(t, `
+	CREATE TABLE events (id INTEGER, at DATE, name TEXT[], PRIMARY KEY (id, at)) PARTITION BY RANGE (at);
+	CREATE TABLE events_2024 PARTITION OF events FOR VALUES FROM ('2024-01-01') TO ('2025-01-01');
+	CREATE VIEW recent_events AS SELECT id, at FROM events WHERE at > now() - interval '1 day';
+	CREATE MATERIALIZED VIEW event_counts AS SELECT at, count(*) AS n, max(name) AS names FROM events GROUP BY at;
+	CREATE UNIQUE INDEX event_counts_at_key ON event_counts (at);
+	`)
+
+	schema, err := NewIntrospector(db).Introspect(context.Background(), sqlgen.IntrospectOptions{Schema: "public"})
+	if err != nil {
+		t.Fatalf("failed to introspect: %s", err)
+	}
+
+	var tables []string
+	for _, table := range schema.Tables {
+		tables = append(tables, table.Name+":"+string(table.Kind))
+	}
+	expected := []string{"event_counts:materialized_view", "events:partitioned_table", "recent_events:view"}
+	if !reflect.DeepEqual(tables, expected) {
+		t.Errorf("expected tables %v, got %v", expected, tables)
+	}
+
+	counts := schema.Table("event_counts")
+	var types []string
+	for _, c := range counts.Columns {
+		types = append(types, c.Name+":"+c.DataType)
+	}
+	if expected := []string{"at:date", "n:bigint", "names:ARRAY"}; !reflect.DeepEqual(types, expected) {
+		t.Errorf("expected materialized view columns %v, got %v", expected, types)
+	}
+	if index := counts.Index("event_counts_at_key"); index == nil || !index.Unique {
+		t.Errorf("unexpected materialized view index: %v", index)
+	}
+	if pk := schema.Table("events").PrimaryKey; pk == nil || !reflect.DeepEqual(pk.Columns, []string{"id", "at"}) {
+		t.Errorf("unexpected partitioned table primary key: %v", pk)
+	}
+}
+
+func TestIntrospectGeneratedColumnsIntegration(t *testing.T) {
+	db := setupPostgres(t, `
+	CREATE TABLE orders (
+		id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+		ref INTEGER GENERATED BY DEFAULT AS IDENTITY,
+		price INTEGER NOT NULL,
+		total INTEGER GENERATED ALWAYS AS (price * 2) STORED,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	);
+	`)
+
+	schema, err := NewIntrospector(db).Introspect(context.Background(), sqlgen.IntrospectOptions{Schema: "public"})
+	if err != nil {
+		t.Fatalf("failed to introspect: %s", err)
+	}
+
+	orders := schema.Table("orders")
+	if c := orders.Column("id"); !c.AutoIncrement || c.Identity != sqlgen.IdentityAlways {
+		t.Errorf("unexpected identity column: %+v", c)
+	}
+	if c := orders.Column("ref"); !c.AutoIncrement || c.Identity != sqlgen.IdentityByDefault {
+		t.Errorf("unexpected identity column: %+v", c)
+	}
+	if c := orders.Column("total"); !c.Generated || c.AutoIncrement {
+		t.Errorf("unexpected generated column: %+v", c)
+	}
+	if c := orders.Column("created_at"); c.Default == nil || c.Generated || c.AutoIncrement {
+		t.Errorf("unexpected defaulted column: %+v", c)
+	}
+}
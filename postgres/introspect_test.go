@@ -0,0 +1,40 @@
+package postgres
+
+import "testing"
+
+func TestBuildSkipTablesCondition(t *testing.T) {
+	tests := []struct {
+		name       string
+		skipTables []string
+		baseIndex  int
+		expected   string
+	}{
+		{
+			name:       "empty list",
+			skipTables: []string{},
+			baseIndex:  2,
+			expected:   "",
+		},
+		{
+			name:       "single table",
+			skipTables: []string{"users"},
+			baseIndex:  2,
+			expected:   "AND c.table_name NOT IN ($2)",
+		},
+		{
+			name:       "multiple tables",
+			skipTables: []string{"users", "posts", "comments"},
+			baseIndex:  2,
+			expected:   "AND c.table_name NOT IN ($2, $3, $4)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := buildSkipTablesCondition(tt.skipTables, tt.baseIndex)
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package sqlgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// repoDBTX declares the interface the repository functions run their
+// queries on.
+const repoDBTX = `
+// DBTX is implemented by *sql.DB, *sql.Tx and *sql.Conn.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+`
+
+// WriteRepository writes a Go file of package pkg with a function running
+// each of queries through database/sql, e.g. GetUserByPk(ctx, db, id). Rows
+// are scanned into the structs of models, which have to be declared in the
+// same package, e.g. by WriteModels.
+func (g *Generator) WriteRepository(w io.Writer, pkg string, queries []Query, models []Model) error {
+	byTable := make(map[*Table]Model, len(models))
+	for _, m := range models {
+		byTable[m.Table] = m
+	}
+	imports := []string{"context", "database/sql"}
+	var funcs bytes.Buffer
+	for _, q := range queries {
+		m, ok := byTable[q.Table]
+		if !ok {
+			return fmt.Errorf("no model for table %s of query %s", q.Table.Name, q.Name)
+		}
+		params, types := g.repoParams(q)
+		for _, typ := range types {
+			imports = addImport(imports, typ)
+		}
+		writeRepoFunc(&funcs, q, m, params, types)
+	}
+
+	var b bytes.Buffer
+	writeGoHeader(&b, pkg, imports)
+	b.WriteString(repoDBTX)
+	b.Write(funcs.Bytes())
+	return writeGoSource(w, b.Bytes())
+}
+
+// repoLocals are the names the repository functions use besides their
+// parameters, including the imported packages.
+var repoLocals = []string{"ctx", "db", "err", "i", "items", "rows", "count", "result", "context", "sql", "json", "time"}
+
+// repoParams returns the Go names and types of the parameters of q. Names
+// clashing with keywords, the locals of the function or each other get a
+// number.
+func (g *Generator) repoParams(q Query) (names, types []string) {
+	used := map[string]bool{queryConst(q): true}
+	for _, name := range repoLocals {
+		used[name] = true
+	}
+	for _, p := range q.Params {
+		name := goVarName(p.Name)
+		for base, i := name, 2; used[name] || token.IsKeyword(name); i++ {
+			name = base + strconv.Itoa(i)
+		}
+		used[name] = true
+		names = append(names, name)
+
+		typ := "int32"
+		if p.Column != nil {
+			typ = goType(p.Column, g.NullPointers)
+		}
+		types = append(types, typ)
+	}
+	return names, types
+}
+
+// queryConst returns the name of the constant holding the SQL of q, e.g.
+// getUserByPk.
+func queryConst(q Query) string {
+	return strings.ToLower(q.Name[:1]) + q.Name[1:]
+}
+
+// writeRepoFunc writes the query constant and the function running q to b.
+func writeRepoFunc(b *bytes.Buffer, q Query, m Model, params, types []string) {
+	query := queryConst(q)
+	args := make([]string, len(params))
+	for i := range params {
+		args[i] = params[i] + " " + types[i]
+	}
+	signature := fmt.Sprintf("func %s(ctx context.Context, db DBTX", q.Name)
+	if len(args) > 0 {
+		signature += ", " + strings.Join(args, ", ")
+	}
+	call := query
+	if len(params) > 0 {
+		call += ", " + strings.Join(params, ", ")
+	}
+	scan := make([]string, len(m.Fields))
+	for i, f := range m.Fields {
+		scan[i] = "&i." + f.Name
+	}
+
+	fmt.Fprintf(b, "\nconst %s = %s\n\n// %s runs %s.\n", query, goString(q.SQL), q.Name, query)
+	switch {
+	case q.Cmd == ":exec":
+		fmt.Fprintf(b, "%s) (sql.Result, error) {\n\treturn db.ExecContext(ctx, %s)\n}\n", signature, call)
+	case q.Cmd == ":execrows":
+		fmt.Fprintf(b, "%s) (int64, error) {\n", signature)
+		fmt.Fprintf(b, "\tresult, err := db.ExecContext(ctx, %s)\n", call)
+		b.WriteString("\tif err != nil {\n\t\treturn 0, err\n\t}\n\treturn result.RowsAffected()\n}\n")
+	case q.Cmd == ":many":
+		fmt.Fprintf(b, "%s) ([]%s, error) {\n", signature, m.Name)
+		fmt.Fprintf(b, "\trows, err := db.QueryContext(ctx, %s)\n", call)
+		b.WriteString("\tif err != nil {\n\t\treturn nil, err\n\t}\n\tdefer rows.Close()\n")
+		fmt.Fprintf(b, "\tvar items []%s\n\tfor rows.Next() {\n\t\tvar i %s\n", m.Name, m.Name)
+		fmt.Fprintf(b, "\t\tif err := rows.Scan(%s); err != nil {\n\t\t\treturn nil, err\n\t\t}\n", strings.Join(scan, ", "))
+		b.WriteString("\t\titems = append(items, i)\n\t}\n\treturn items, rows.Err()\n}\n")
+	case q.Action == ActionCountByFK:
+		fmt.Fprintf(b, "%s) (int64, error) {\n", signature)
+		fmt.Fprintf(b, "\tvar count int64\n\terr := db.QueryRowContext(ctx, %s).Scan(&count)\n\treturn count, err\n}\n", call)
+	default:
+		fmt.Fprintf(b, "%s) (%s, error) {\n\tvar i %s\n", signature, m.Name, m.Name)
+		fmt.Fprintf(b, "\terr := db.QueryRowContext(ctx, %s).Scan(%s)\n\treturn i, err\n}\n", call, strings.Join(scan, ", "))
+	}
+}
+
+// goString renders s as a raw string literal, or as an interpreted one
+// when s contains a backquote, e.g. a quoted MySQL identifier.
+func goString(s string) string {
+	if strings.Contains(s, "`") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
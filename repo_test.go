@@ -0,0 +1,87 @@
+package sqlgen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteRepository(t *testing.T) {
+	table := &Table{
+		Name: "users",
+		Columns: []*Column{
+			{Name: "id", OrdinalPosition: 1, DataType: "bigint", AutoIncrement: true},
+			{Name: "name", OrdinalPosition: 2, DataType: "text"},
+			{Name: "type", OrdinalPosition: 3, DataType: "text", Nullable: true},
+		},
+		PrimaryKey: &PrimaryKey{Name: "users_pkey", Columns: []string{"id"}},
+	}
+	schema := &Schema{Tables: []*Table{table}}
+
+	tests := []struct {
+		name      string
+		generator Generator
+		expected  string
+	}{
+		{
+			name:      "postgres",
+			generator: Generator{Dialect: PostgresDialect{}, Actions: []Action{ActionCreate, ActionRead, ActionDelete, ActionList}},
+			expected: "const createUser = `INSERT INTO users (name, type) VALUES ($1, $2) RETURNING *;`\n\n" +
+				"// CreateUser runs createUser.\n" +
+				"func CreateUser(ctx context.Context, db DBTX, name string, type2 sql.NullString) (User, error) {\n" +
+				"\tvar i User\n" +
+				"\terr := db.QueryRowContext(ctx, createUser, name, type2).Scan(&i.ID, &i.Name, &i.Type)\n" +
+				"\treturn i, err\n" +
+				"}\n\n" +
+				"const getUserByPk = `SELECT id, name, type FROM users WHERE id = $1;`\n\n" +
+				"// GetUserByPk runs getUserByPk.\n" +
+				"func GetUserByPk(ctx context.Context, db DBTX, id int64) (User, error) {\n" +
+				"\tvar i User\n" +
+				"\terr := db.QueryRowContext(ctx, getUserByPk, id).Scan(&i.ID, &i.Name, &i.Type)\n" +
+				"\treturn i, err\n" +
+				"}\n\n" +
+				"const deleteUser = `DELETE FROM users WHERE id = $1;`\n\n" +
+				"// DeleteUser runs deleteUser.\n" +
+				"func DeleteUser(ctx context.Context, db DBTX, id int64) (int64, error) {\n" +
+				"\tresult, err := db.ExecContext(ctx, deleteUser, id)\n" +
+				"\tif err != nil {\n\t\treturn 0, err\n\t}\n" +
+				"\treturn result.RowsAffected()\n" +
+				"}\n\n" +
+				"const listUsers = `SELECT id, name, type FROM users ORDER BY id LIMIT $1 OFFSET $2;`\n\n" +
+				"// ListUsers runs listUsers.\n" +
+				"func ListUsers(ctx context.Context, db DBTX, limit int32, offset int32) ([]User, error) {\n" +
+				"\trows, err := db.QueryContext(ctx, listUsers, limit, offset)\n" +
+				"\tif err != nil {\n\t\treturn nil, err\n\t}\n" +
+				"\tdefer rows.Close()\n" +
+				"\tvar items []User\n" +
+				"\tfor rows.Next() {\n" +
+				"\t\tvar i User\n" +
+				"\t\tif err := rows.Scan(&i.ID, &i.Name, &i.Type); err != nil {\n\t\t\treturn nil, err\n\t\t}\n" +
+				"\t\titems = append(items, i)\n" +
+				"\t}\n" +
+				"\treturn items, rows.Err()\n" +
+				"}\n\n" +
+				"const listUsersAfter = `SELECT id, name, type FROM users WHERE id > $1 ORDER BY id LIMIT $2;`\n\n",
+		},
+		{
+			name:      "mysql",
+			generator: Generator{Dialect: MySQLDialect{}, Actions: []Action{ActionUpdate}, NullPointers: true, QuoteIdentifiers: true},
+			expected: "const updateUser = \"UPDATE `users` SET `name` = ?, `type` = ? WHERE `id` = ?;\"\n\n" +
+				"// UpdateUser runs updateUser.\n" +
+				"func UpdateUser(ctx context.Context, db DBTX, name string, type2 *string, id int64) (sql.Result, error) {\n" +
+				"\treturn db.ExecContext(ctx, updateUser, name, type2, id)\n" +
+				"}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf strings.Builder
+			g := &tt.generator
+			if err := g.WriteRepository(&buf, "db", g.Generate(schema), g.Models(schema)); err != nil {
+				t.Fatalf("WriteRepository failed: %v", err)
+			}
+			if !strings.Contains(buf.String(), tt.expected) {
+				t.Errorf("expected to contain:\n%s\ngot:\n%s", tt.expected, buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package sqlgen
+
+// postgresReserved lists the key words of PostgreSQL that cannot name a
+// table or column unless quoted: the reserved ones and the ones only usable
+// as type or function names.
+var postgresReserved = map[string]bool{
+	"all": true, "analyse": true, "analyze": true, "and": true, "any": true,
+	"array": true, "as": true, "asc": true, "asymmetric": true,
+	"authorization": true, "binary": true, "both": true, "case": true,
+	"cast": true, "check": true, "collate": true, "collation": true,
+	"column": true, "concurrently": true, "constraint": true, "create": true,
+	"cross": true, "current_catalog": true, "current_date": true,
+	"current_role": true, "current_schema": true, "current_time": true,
+	"current_timestamp": true, "current_user": true, "default": true,
+	"deferrable": true, "desc": true, "distinct": true, "do": true,
+	"else": true, "end": true, "except": true, "false": true, "fetch": true,
+	"for": true, "foreign": true, "freeze": true, "from": true, "full": true,
+	"grant": true, "group": true, "having": true, "ilike": true, "in": true,
+	"initially": true, "inner": true, "intersect": true, "into": true,
+	"is": true, "isnull": true, "join": true, "lateral": true,
+	"leading": true, "left": true, "like": true, "limit": true,
+	"localtime": true, "localtimestamp": true, "natural": true, "not": true,
+	"notnull": true, "null": true, "offset": true, "on": true, "only": true,
+	"or": true, "order": true, "outer": true, "overlaps": true,
+	"placing": true, "primary": true, "references": true, "returning": true,
+	"right": true, "select": true, "session_user": true, "similar": true,
+	"some": true, "symmetric": true, "system_user": true, "table": true,
+	"tablesample": true, "then": true, "to": true, "trailing": true,
+	"true": true, "union": true, "unique": true, "user": true, "using": true,
+	"variadic": true, "verbose": true, "when": true, "where": true,
+	"window": true, "with": true,
+}
+
+// mysqlReserved lists the reserved words of MySQL 8.0 and 8.4, which cannot
+// name a table or column unless quoted.
+var mysqlReserved = map[string]bool{
+	"accessible": true, "add": true, "all": true, "alter": true,
+	"analyze": true, "and": true, "as": true, "asc": true,
+	"asensitive": true, "before": true, "between": true, "bigint": true,
+	"binary": true, "blob": true, "both": true, "by": true, "call": true,
+	"cascade": true, "case": true, "change": true, "char": true,
+	"character": true, "check": true, "collate": true, "column": true,
+	"condition": true, "constraint": true, "continue": true, "convert": true,
+	"create": true, "cross": true, "cube": true, "cume_dist": true,
+	"current_date": true, "current_time": true, "current_timestamp": true,
+	"current_user": true, "cursor": true, "database": true,
+	"databases": true, "day_hour": true, "day_microsecond": true,
+	"day_minute": true, "day_second": true, "dec": true, "decimal": true,
+	"declare": true, "default": true, "delayed": true, "delete": true,
+	"dense_rank": true, "desc": true, "describe": true,
+	"deterministic": true, "distinct": true, "distinctrow": true,
+	"div": true, "double": true, "drop": true, "dual": true, "each": true,
+	"else": true, "elseif": true, "empty": true, "enclosed": true,
+	"escaped": true, "except": true, "exists": true, "exit": true,
+	"explain": true, "false": true, "fetch": true, "first_value": true,
+	"float": true, "float4": true, "float8": true, "for": true,
+	"force": true, "foreign": true, "from": true, "fulltext": true,
+	"function": true, "generated": true, "get": true, "grant": true,
+	"group": true, "grouping": true, "groups": true, "having": true,
+	"high_priority": true, "hour_microsecond": true, "hour_minute": true,
+	"hour_second": true, "if": true, "ignore": true, "in": true,
+	"index": true, "infile": true, "inner": true, "inout": true,
+	"insensitive": true, "insert": true, "int": true, "int1": true,
+	"int2": true, "int3": true, "int4": true, "int8": true, "integer": true,
+	"intersect": true, "interval": true, "into": true,
+	"io_after_gtids": true, "io_before_gtids": true, "is": true,
+	"iterate": true, "join": true, "json_table": true, "key": true,
+	"keys": true, "kill": true, "lag": true, "last_value": true,
+	"lateral": true, "lead": true, "leading": true, "leave": true,
+	"left": true, "like": true, "limit": true, "linear": true, "lines": true,
+	"load": true, "localtime": true, "localtimestamp": true, "lock": true,
+	"long": true, "longblob": true, "longtext": true, "loop": true,
+	"low_priority": true, "manual": true, "master_bind": true,
+	"master_ssl_verify_server_cert": true, "match": true, "maxvalue": true,
+	"mediumblob": true, "mediumint": true, "mediumtext": true,
+	"middleint": true, "minute_microsecond": true, "minute_second": true,
+	"mod": true, "modifies": true, "natural": true, "not": true,
+	"no_write_to_binlog": true, "nth_value": true, "ntile": true,
+	"null": true, "numeric": true, "of": true, "on": true, "optimize": true,
+	"optimizer_costs": true, "option": true, "optionally": true, "or": true,
+	"order": true, "out": true, "outer": true, "outfile": true, "over": true,
+	"parallel": true, "partition": true, "percent_rank": true,
+	"precision": true, "primary": true, "procedure": true, "purge": true,
+	"qualify": true, "range": true, "rank": true, "read": true, "reads": true,
+	"read_write": true, "real": true, "recursive": true, "references": true,
+	"regexp": true, "release": true, "rename": true, "repeat": true,
+	"replace": true, "require": true, "resignal": true, "restrict": true,
+	"return": true, "revoke": true, "right": true, "rlike": true, "row": true,
+	"rows": true, "row_number": true, "schema": true, "schemas": true,
+	"second_microsecond": true, "select": true, "sensitive": true,
+	"separator": true, "set": true, "show": true, "signal": true,
+	"smallint": true, "spatial": true, "specific": true, "sql": true,
+	"sqlexception": true, "sqlstate": true, "sqlwarning": true,
+	"sql_big_result": true, "sql_calc_found_rows": true,
+	"sql_small_result": true, "ssl": true, "starting": true, "stored": true,
+	"straight_join": true, "system": true, "table": true,
+	"tablesample": true, "terminated": true, "then": true, "tinyblob": true,
+	"tinyint": true, "tinytext": true, "to": true, "trailing": true,
+	"trigger": true, "true": true, "undo": true, "union": true,
+	"unique": true, "unlock": true, "unsigned": true, "update": true,
+	"usage": true, "use": true, "using": true, "utc_date": true,
+	"utc_time": true, "utc_timestamp": true, "values": true,
+	"varbinary": true, "varchar": true, "varcharacter": true,
+	"varying": true, "virtual": true, "when": true, "where": true,
+	"while": true, "window": true, "with": true, "write": true, "xor": true,
+	"year_month": true, "zerofill": true,
+}
@@ -0,0 +1,201 @@
+package sqlgen
+
+// Schema is the database structure read by introspection.
+type Schema struct {
+	// Name is the schema (PostgreSQL) or database (MySQL) name.
+	Name   string   `json:"name"`
+	Tables []*Table `json:"tables"`
+}
+
+// Table describes a single table, view or other relation with columns and
+// its constraints.
+type Table struct {
+	Schema string `json:"schema"`
+	Name   string `json:"name"`
+	// Kind is the kind of relation; empty for ordinary tables.
+	Kind        TableKind     `json:"kind,omitempty"`
+	Columns     []*Column     `json:"columns"`
+	PrimaryKey  *PrimaryKey   `json:"primary_key,omitempty"`
+	Indexes     []*Index      `json:"indexes,omitempty"`
+	ForeignKeys []*ForeignKey `json:"foreign_keys,omitempty"`
+}
+
+// TableKind is the kind of a relation. Partitions are not read on their own
+// but through their partitioned table.
+type TableKind string
+
+const (
+	KindBaseTable        TableKind = ""
+	KindView             TableKind = "view"
+	KindMaterializedView TableKind = "materialized_view"
+	// KindPartitionedTable is the parent table of partitions (PostgreSQL).
+	KindPartitionedTable TableKind = "partitioned_table"
+	// KindForeignTable is a table of a foreign server (PostgreSQL).
+	KindForeignTable TableKind = "foreign_table"
+)
+
+// Column describes a single column of a table.
+type Column struct {
+	Name string `json:"name"`
+	// OrdinalPosition is the 1-based position of the column in the table.
+	OrdinalPosition int    `json:"ordinal_position"`
+	DataType        string `json:"data_type"`
+	Nullable        bool   `json:"nullable"`
+	// Default is the default expression, or nil when the column has none.
+	Default *string `json:"default,omitempty"`
+	// AutoIncrement reports whether the value is assigned by the database,
+	// e.g. serial and identity columns on PostgreSQL or AUTO_INCREMENT on
+	// MySQL.
+	AutoIncrement bool `json:"auto_increment,omitempty"`
+	// Identity is set for identity columns on PostgreSQL, which are
+	// AutoIncrement too.
+	Identity Identity `json:"identity,omitempty"`
+	// Generated reports whether the value is computed from other columns,
+	// e.g. GENERATED ALWAYS AS (...) STORED. It can never be written.
+	Generated bool `json:"generated,omitempty"`
+	// Unsigned reports whether a numeric column of MySQL is UNSIGNED.
+	Unsigned bool `json:"unsigned,omitempty"`
+}
+
+// Identity tells whether an identity column takes explicit values.
+type Identity string
+
+const (
+	// IdentityAlways rejects explicit values unless the INSERT overrides the
+	// system value.
+	IdentityAlways Identity = "always"
+	// IdentityByDefault takes explicit values like a serial column.
+	IdentityByDefault Identity = "by_default"
+)
+
+// PrimaryKey is the primary key constraint of a table.
+type PrimaryKey struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+}
+
+// Index is an index or unique constraint of a table.
+type Index struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+	Unique  bool     `json:"unique"`
+}
+
+// ForeignKey is a foreign key constraint of a table.
+type ForeignKey struct {
+	Name              string   `json:"name"`
+	Columns           []string `json:"columns"`
+	ReferencedSchema  string   `json:"referenced_schema"`
+	ReferencedTable   string   `json:"referenced_table"`
+	ReferencedColumns []string `json:"referenced_columns"`
+}
+
+// Table returns the table with the given name, or nil if it does not exist.
+func (s *Schema) Table(name string) *Table {
+	for _, t := range s.Tables {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// Column returns the column with the given name, or nil if it does not exist.
+func (t *Table) Column(name string) *Column {
+	for _, c := range t.Columns {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// Index returns the index with the given name, or nil if it does not exist.
+func (t *Table) Index(name string) *Index {
+	for _, index := range t.Indexes {
+		if index.Name == name {
+			return index
+		}
+	}
+	return nil
+}
+
+// ForeignKey returns the foreign key with the given name, or nil if it does
+// not exist.
+func (t *Table) ForeignKey(name string) *ForeignKey {
+	for _, fk := range t.ForeignKeys {
+		if fk.Name == name {
+			return fk
+		}
+	}
+	return nil
+}
+
+// IsPrimaryKey reports whether the column is part of the primary key.
+func (t *Table) IsPrimaryKey(name string) bool {
+	if t.PrimaryKey == nil {
+		return false
+	}
+	for _, c := range t.PrimaryKey.Columns {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+// PrimaryKeyColumns returns the primary key columns in key order.
+// It returns nil when the table has no primary key.
+func (t *Table) PrimaryKeyColumns() []*Column {
+	if t.PrimaryKey == nil {
+		return nil
+	}
+	return t.ColumnsByName(t.PrimaryKey.Columns)
+}
+
+// ColumnsByName returns the named columns in the given order. It returns nil
+// when one of them does not exist.
+func (t *Table) ColumnsByName(names []string) []*Column {
+	var cols []*Column
+	for _, name := range names {
+		c := t.Column(name)
+		if c == nil {
+			return nil
+		}
+		cols = append(cols, c)
+	}
+	return cols
+}
+
+// ReadOnly reports whether t only gets queries reading rows: views,
+// materialized views and foreign tables.
+func (t *Table) ReadOnly() bool {
+	switch t.Kind {
+	case KindView, KindMaterializedView, KindForeignTable:
+		return true
+	}
+	return false
+}
+
+// UpdateColumns returns the columns that should be set by an UPDATE by
+// primary key, skipping the key itself and the ones assigned or computed by
+// the database.
+func (t *Table) UpdateColumns() []*Column {
+	var cols []*Column
+	for _, c := range t.Columns {
+		if c.AutoIncrement || c.Generated || t.IsPrimaryKey(c.Name) {
+			continue
+		}
+		cols = append(cols, c)
+	}
+	return cols
+}
+
+// ColumnNames returns the names of the given columns.
+func ColumnNames(cols []*Column) []string {
+	names := make([]string, len(cols))
+	for i, c := range cols {
+		names[i] = c.Name
+	}
+	return names
+}
@@ -0,0 +1,88 @@
+package sqlgen
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTablePrimaryKeyColumns(t *testing.T) {
+	table := &Table{
+		Name: "order_items",
+		Columns: []*Column{
+			{Name: "item_id", OrdinalPosition: 1},
+			{Name: "order_id", OrdinalPosition: 2},
+			{Name: "quantity", OrdinalPosition: 3},
+		},
+		PrimaryKey: &PrimaryKey{Columns: []string{"order_id", "item_id"}},
+	}
+
+	got := ColumnNames(table.PrimaryKeyColumns())
+	if want := []string{"order_id", "item_id"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("PrimaryKeyColumns() = %v; want %v", got, want)
+	}
+	if !table.IsPrimaryKey("item_id") || table.IsPrimaryKey("quantity") {
+		t.Errorf("IsPrimaryKey() reported wrong columns")
+	}
+
+	table.PrimaryKey = nil
+	if cols := table.PrimaryKeyColumns(); cols != nil {
+		t.Errorf("PrimaryKeyColumns() = %v; want nil", cols)
+	}
+}
+
+func TestSchemaTable(t *testing.T) {
+	schema := &Schema{Tables: []*Table{{Name: "users"}, {Name: "posts"}}}
+	if table := schema.Table("posts"); table == nil || table.Name != "posts" {
+		t.Errorf("Table(%q) = %v", "posts", table)
+	}
+	if table := schema.Table("tags"); table != nil {
+		t.Errorf("Table(%q) = %v; want nil", "tags", table)
+	}
+}
+
+func TestTableUpdateColumns(t *testing.T) {
+	table := &Table{
+		Name: "users",
+		Columns: []*Column{
+			{Name: "id"},
+			{Name: "name"},
+			{Name: "seq", AutoIncrement: true},
+			{Name: "email", Nullable: true},
+			{Name: "domain", Generated: true},
+		},
+		PrimaryKey: &PrimaryKey{Columns: []string{"id"}},
+	}
+
+	got := ColumnNames(table.UpdateColumns())
+	if want := []string{"name", "email"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("UpdateColumns() = %v; want %v", got, want)
+	}
+}
+
+func TestTableLookups(t *testing.T) {
+	table := &Table{
+		Name:    "users",
+		Columns: []*Column{{Name: "id"}, {Name: "name"}, {Name: "email"}},
+		Indexes: []*Index{{Name: "users_email_key", Columns: []string{"email"}, Unique: true}},
+		ForeignKeys: []*ForeignKey{
+			{Name: "users_id_fkey", Columns: []string{"id"}, ReferencedTable: "accounts", ReferencedColumns: []string{"id"}},
+		},
+	}
+
+	got := ColumnNames(table.ColumnsByName([]string{"email", "id"}))
+	if want := []string{"email", "id"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ColumnsByName() = %v; want %v", got, want)
+	}
+	if cols := table.ColumnsByName([]string{"email", "missing"}); cols != nil {
+		t.Errorf("ColumnsByName() = %v; want nil", cols)
+	}
+	if index := table.Index("users_email_key"); index == nil || !index.Unique {
+		t.Errorf("Index() = %v", index)
+	}
+	if fk := table.ForeignKey("users_id_fkey"); fk == nil || fk.ReferencedTable != "accounts" {
+		t.Errorf("ForeignKey() = %v", fk)
+	}
+	if fk := table.ForeignKey("missing"); fk != nil {
+		t.Errorf("ForeignKey() = %v; want nil", fk)
+	}
+}
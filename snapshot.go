@@ -0,0 +1,51 @@
+package sqlgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// SnapshotVersion is the version of the snapshot format written by
+// WriteSnapshot.
+const SnapshotVersion = 1
+
+// Snapshot is a schema saved as JSON, so that queries can be generated again
+// without access to the database.
+type Snapshot struct {
+	Version int `json:"version"`
+	// Dialect is the name of the database the schema was read from,
+	// e.g. "postgres".
+	Dialect string `json:"dialect"`
+	// DefaultSchema is the schema whose tables were not qualified by it,
+	// e.g. the database of the DSN on MySQL.
+	DefaultSchema string  `json:"default_schema,omitempty"`
+	Schema        *Schema `json:"schema"`
+}
+
+// WriteSnapshot writes schema, read from a database of dialect d with the
+// default schema defaultSchema, as an indented JSON snapshot.
+func WriteSnapshot(w io.Writer, d Dialect, defaultSchema string, schema *Schema) error {
+	s := Snapshot{Version: SnapshotVersion, Dialect: d.Name(), DefaultSchema: defaultSchema, Schema: schema}
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+// ReadSnapshot reads a snapshot written by WriteSnapshot.
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	var s Snapshot
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return nil, fmt.Errorf("invalid snapshot: %w", err)
+	}
+	if s.Version != SnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d; expected %d", s.Version, SnapshotVersion)
+	}
+	if s.Dialect == "" || s.Schema == nil {
+		return nil, fmt.Errorf("invalid snapshot: missing dialect or schema")
+	}
+	return &s, nil
+}
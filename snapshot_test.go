@@ -0,0 +1,62 @@
+package sqlgen
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	def := "1"
+	schema := &Schema{
+		Name: "public",
+		Tables: []*Table{{
+			Schema: "public",
+			Name:   "order_items",
+			Columns: []*Column{
+				{Name: "order_id", OrdinalPosition: 1, DataType: "integer"},
+				{Name: "quantity", OrdinalPosition: 2, DataType: "integer", Default: &def},
+				{Name: "id", OrdinalPosition: 3, DataType: "bigint", AutoIncrement: true},
+			},
+			PrimaryKey:  &PrimaryKey{Name: "order_items_pkey", Columns: []string{"id"}},
+			Indexes:     []*Index{{Name: "order_items_order_id_key", Columns: []string{"order_id"}, Unique: true}},
+			ForeignKeys: []*ForeignKey{{Name: "order_items_order_id_fkey", Columns: []string{"order_id"}, ReferencedSchema: "public", ReferencedTable: "orders", ReferencedColumns: []string{"id"}}},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSnapshot(&buf, PostgresDialect{}, "public", schema); err != nil {
+		t.Fatalf("WriteSnapshot failed: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "{\n  \"version\": 1,\n  \"dialect\": \"postgres\",\n") {
+		t.Errorf("unexpected snapshot:\n%s", buf.String())
+	}
+
+	snapshot, err := ReadSnapshot(&buf)
+	if err != nil {
+		t.Fatalf("ReadSnapshot failed: %v", err)
+	}
+	if snapshot.Dialect != "postgres" || snapshot.DefaultSchema != "public" {
+		t.Errorf("expected dialect %q and default schema %q, got %q and %q", "postgres", "public", snapshot.Dialect, snapshot.DefaultSchema)
+	}
+	if !reflect.DeepEqual(snapshot.Schema, schema) {
+		t.Errorf("schema changed in round trip: %+v", snapshot.Schema.Tables[0])
+	}
+}
+
+func TestReadSnapshotErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{src: "not json", want: "invalid snapshot"},
+		{src: `{"version": 2, "dialect": "postgres", "schema": {}}`, want: "unsupported snapshot version 2"},
+		{src: `{"version": 1, "schema": {}}`, want: "missing dialect or schema"},
+	}
+	for _, tt := range tests {
+		if _, err := ReadSnapshot(strings.NewReader(tt.src)); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.src, tt.want, err)
+		}
+	}
+}
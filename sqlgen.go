@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"unicode"
 
 	"github.com/jinzhu/inflection"
 	"golang.org/x/text/cases"
@@ -27,29 +28,75 @@ func SnakeToPascal(input string) string {
 	return strings.Join(words, "")
 }
 
-// GetTableName extracts the table name from an INSERT SQL statement.
+// tableNamePrefixes match the keywords preceding the table name of a
+// statement, in the order GetTableName tries them.
+var tableNamePrefixes = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\bINSERT\s+INTO\s+`),
+	regexp.MustCompile(`(?i)\bFROM\s+`),
+}
+
+// GetTableName extracts the table name from an INSERT or SELECT statement.
+// The name keeps its case and quoted names are unquoted, e.g. UserProfiles
+// for "UserProfiles". A schema-qualified name is returned as schema.table.
 func GetTableName(sql string) (string, error) {
-	// Normalize and trim the SQL statement
-	sql = strings.TrimSpace(sql)
-	sql = strings.ToUpper(sql)
-
-	// Regular expression to match the table name after "INSERT INTO"
-	re := regexp.MustCompile(`(?i)INSERT\s+INTO\s+([^\s\(\)]+)`)
-	match := re.FindStringSubmatch(sql)
-	if len(match) == 2 {
-		return strings.ToLower(match[1]), nil
+	for _, re := range tableNamePrefixes {
+		loc := re.FindStringIndex(sql)
+		if loc == nil {
+			continue
+		}
+		if name, ok := scanTableName(sql[loc[1]:]); ok {
+			return name, nil
+		}
 	}
+	return "", fmt.Errorf("failed to extract table name from SQL: %s", sql)
+}
 
-	re2 := regexp.MustCompile(`FROM\s+([^\s\(\)]+)`)
-	match2 := re2.FindStringSubmatch(sql)
-	if len(match2) < 2 {
-		return "", fmt.Errorf("failed to extract table name from SQL: %s", sql)
+// scanTableName returns the unquoted, possibly schema-qualified name at the
+// start of s.
+func scanTableName(s string) (string, bool) {
+	var parts []string
+	for {
+		var part string
+		if s != "" && (s[0] == '"' || s[0] == '`') {
+			quote := s[:1]
+			end := 1
+			for {
+				i := strings.Index(s[end:], quote)
+				if i < 0 {
+					return "", false
+				}
+				end += i + 1
+				if !strings.HasPrefix(s[end:], quote) {
+					break
+				}
+				// a doubled quote stands for the quote itself
+				end++
+			}
+			part, s = strings.ReplaceAll(s[1:end-1], quote+quote, quote), s[end:]
+		} else {
+			end := strings.IndexFunc(s, func(r rune) bool {
+				return unicode.IsSpace(r) || strings.ContainsRune("().,;\"`", r)
+			})
+			if end < 0 {
+				end = len(s)
+			}
+			if end == 0 {
+				return "", false
+			}
+			part, s = s[:end], s[end:]
+		}
+		parts = append(parts, part)
+		if !strings.HasPrefix(s, ".") {
+			return strings.Join(parts, "."), true
+		}
+		s = s[1:]
 	}
-
-	// Return the table name
-	return strings.ToLower(match2[1]), nil
 }
 
 func Singularize(str string) string {
 	return inflection.Singular(str)
 }
+
+func Pluralize(str string) string {
+	return inflection.Plural(str)
+}
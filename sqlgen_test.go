@@ -3,21 +3,49 @@ package sqlgen
 import "testing"
 
 func TestGetTableName(t *testing.T) {
-	sql := "INSERT INTO users (id, name) VALUES ($1, $2)"
-	tableName, err := GetTableName(sql)
-	if err != nil {
-		t.Errorf("GetTableName failed: %v", err)
+	testCases := []struct {
+		sql      string
+		expected string
+	}{
+		{"INSERT INTO users (id, name) VALUES ($1, $2)", "users"},
+		{"SELECT id, name FROM users", "users"},
+		{`INSERT INTO "UserProfiles" ("ID") VALUES ($1)`, "UserProfiles"},
+		{"select id from Users where id = ?", "Users"},
+		{"INSERT INTO `order`(id) VALUES (?)", "order"},
+		{`DELETE FROM "sales"."odd""name" WHERE id = $1`, `sales.odd"name`},
+		{"SELECT * FROM audit.logs;", "audit.logs"},
 	}
-	if tableName != "users" {
-		t.Errorf("GetTableName failed: %v", tableName)
+
+	for _, tc := range testCases {
+		tableName, err := GetTableName(tc.sql)
+		if err != nil {
+			t.Errorf("GetTableName(%q) failed: %v", tc.sql, err)
+			continue
+		}
+		if tableName != tc.expected {
+			t.Errorf("GetTableName(%q) = %q; want %q", tc.sql, tableName, tc.expected)
+		}
 	}
 
-	sql = "SELECT id, name FROM users"
-	tableName, err = GetTableName(sql)
-	if err != nil {
-		t.Errorf("GetTableName failed: %v", err)
+	if _, err := GetTableName("SELECT 1"); err == nil {
+		t.Error("expected error for a statement without table")
 	}
-	if tableName != "users" {
-		t.Errorf("GetTableName failed: %v", tableName)
+}
+
+func TestPluralize(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{"user", "users"},
+		{"category", "categories"},
+		{"person", "people"},
+		{"order_item", "order_items"},
+	}
+
+	for _, tc := range testCases {
+		if result := Pluralize(tc.input); result != tc.expected {
+			t.Errorf("Pluralize(%q) = %q; want %q", tc.input, result, tc.expected)
+		}
 	}
 }